@@ -26,23 +26,13 @@ func main() {
 	logger := log.New(writer, "logo-ls: ", 0)
 
 	var iconOverride *icons.Override
+	var overrideErr error
 	switch {
 	case command.NoIconOverride:
 		// user opted out; leave nil
-	case command.IconOverrideFile != "":
-		ov, err := icons.LoadOverridesFromPath(command.IconOverrideFile)
-		if err != nil {
-			logger.Printf("ignoring icon overrides: %v\n", err)
-		} else {
-			iconOverride = ov
-		}
 	default:
-		ov, err := icons.LoadOverrides()
-		if err != nil {
-			logger.Printf("ignoring icon overrides: %v\n", err)
-		} else {
-			iconOverride = ov
-		}
+		// broken files and entries are skipped; the rest still applies
+		iconOverride, overrideErr = icons.LoadOverrides(command.IconOverrideFiles...)
 	}
 
 	app := &app.App{
@@ -53,6 +43,7 @@ func main() {
 		FS:           osfs.New(),
 		GitReader:    git.NewStatusReader(git.ExecPorcelain{}),
 		IconOverride: iconOverride,
+		OverrideErr:  overrideErr,
 	}
 
 	app.Run()
@@ -8,7 +8,7 @@ import (
 	iofs "io/fs"
 	"log"
 	"os"
-	"slices"
+	"path"
 	"sort"
 	"strings"
 
@@ -19,6 +19,7 @@ import (
 	"github.com/canta2899/logo-ls/internal/render"
 	isort "github.com/canta2899/logo-ls/internal/sort"
 	"github.com/canta2899/logo-ls/pkg/fs"
+	"golang.org/x/term"
 )
 
 const cannotAccessFmt = "cannot access %q: %v\n"
@@ -32,6 +33,16 @@ type App struct {
 	// GitReader is optional; when nil the app falls back to FS.GitStatus
 	GitReader    *git.StatusReader
 	IconOverride *icons.Override
+	// OverrideErr holds the problems icons.LoadOverrides reported while
+	// loading IconOverride. Run logs them; with --check-overrides they set
+	// the exit code.
+	OverrideErr error
+
+	projects map[string]*project // by listed directory, see projectFor
+	project  *project            // settings for the directory being listed
+
+	inspectors map[inspectorKey]*inspect.Inspector // see inspectorFor
+	audit      auditTally                          // --audit findings so far
 }
 
 // gitStatusFor returns the status map for dir, using the per-app reader when
@@ -51,6 +62,9 @@ type Args struct {
 type RecursiveLookupFrame struct {
 	entry  *DirectoryEntry
 	header string // if non-empty, printed as: "\n<icon><header>:\n"
+	// ancestors are the resolved paths of the directories above entry;
+	// with -L a symlink back to one of them would recurse forever.
+	ancestors []string
 }
 
 func (a *App) Exit() {
@@ -64,10 +78,13 @@ func (a *App) Write(buf *bytes.Buffer) {
 	}
 }
 
+// GetArguments stats every command-line operand and splits them into
+// files and directories, each ordered by the active sort mode, including a
+// project's sort for the working directory. With -U the operands keep their
+// command-line order.
 func (a *App) GetArguments() *Args {
-	slices.Sort(a.Config.FileList)
-
 	args := &Args{}
+	var fileKeys, dirKeys []*inspect.InspectedEntry
 
 	for _, argPath := range a.Config.FileList {
 		abs, err := a.FS.Abs(argPath)
@@ -77,38 +94,119 @@ func (a *App) GetArguments() *Args {
 			continue
 		}
 
-		f, err := a.FS.Open(abs)
+		fi, err := a.operandInfo(abs, a.hasTrailingSeparator(argPath))
 		if err != nil {
 			a.Logger.Printf(cannotAccessFmt, argPath, err)
 			a.ExitCode.SetSerious()
 			continue
 		}
 
-		fi, err := f.Stat()
-		if err != nil {
-			a.Logger.Printf("cannot stat %q: %v\n", argPath, err)
-			f.Close()
-			a.ExitCode.SetSerious()
-			continue
-		}
-
-		if fi.IsDir() {
-			args.Dirs = append(args.Dirs, DirectoryEntry{
-				File:    f,
-				AbsPath: abs,
-			})
-		} else {
+		if !fi.IsDir() {
 			args.Files = append(args.Files, FileEntry{
 				Info:    fi,
 				AbsPath: abs,
 			})
-			f.Close() // no need to keep file handles for single files
+			fileKeys = append(fileKeys, a.operandKey(argPath, fi))
+			continue
+		}
+		f, err := a.FS.Open(abs)
+		if err != nil {
+			a.Logger.Printf(cannotAccessFmt, argPath, err)
+			a.ExitCode.SetSerious()
+			continue
 		}
+		args.Dirs = append(args.Dirs, DirectoryEntry{
+			File:    f,
+			AbsPath: abs,
+		})
+		dirKeys = append(dirKeys, a.operandKey(argPath, fi))
 	}
+
+	// operands are named relative to the working directory, so its
+	// project's sort orders them, as it orders that directory's listing
+	mode := a.Config.SortMode
+	if cwd, err := a.FS.Abs("."); err == nil {
+		mode = a.sortModeIn(a.projectFor(cwd))
+	}
+	args.Files = sortOperands(args.Files, fileKeys, mode, a.Config.Reverse)
+	args.Dirs = sortOperands(args.Dirs, dirKeys, mode, a.Config.Reverse)
 	return args
 }
 
+// operandInfo stats a command-line operand, following it when it is a
+// symlink the dereference mode asks to follow, or when it was typed with
+// a trailing separator, which names the directory behind the link. A link
+// whose target is missing is listed as the link itself.
+func (a *App) operandInfo(abs string, trailingSep bool) (fs.FileInfo, error) {
+	fi, err := a.FS.Lstat(abs)
+	if err != nil || fi.Mode()&iofs.ModeSymlink == 0 {
+		return fi, err
+	}
+	if !trailingSep && a.Config.Dereference == cli.DereferenceNone {
+		return fi, nil
+	}
+	target, err := a.FS.Stat(abs)
+	if err != nil {
+		return fi, nil
+	}
+	if trailingSep {
+		return target, nil
+	}
+	if a.Config.Dereference == cli.DereferenceCommandLineDirs && !target.IsDir() {
+		return fi, nil
+	}
+	return target, nil
+}
+
+// hasTrailingSeparator reports whether a command-line operand ends in a
+// path separator, as in "link/".
+func (a *App) hasTrailingSeparator(argPath string) bool {
+	sep := a.FS.Separator()
+	return len(argPath) > len(sep) && strings.HasSuffix(argPath, sep)
+}
+
+// operandKey builds the minimal entry the sorter needs to order a
+// command-line operand. Like GNU ls, operands sort by the name as typed.
+func (a *App) operandKey(argPath string, fi fs.FileInfo) *inspect.InspectedEntry {
+	base, ext := inspect.SplitNameExt(argPath, a.FS)
+	return &inspect.InspectedEntry{
+		Name:    argPath,
+		Base:    base,
+		Ext:     ext,
+		Mode:    fi.Mode(),
+		Size:    fi.Size(),
+		ModTime: fi.ModTime(),
+	}
+}
+
+// sortOperands reorders items so they follow keys once sorted; keys[i]
+// describes items[i].
+func sortOperands[T any](items []T, keys []*inspect.InspectedEntry, mode cli.SortMode, reverse bool) []T {
+	pos := make(map[*inspect.InspectedEntry]int, len(keys))
+	for i, k := range keys {
+		pos[k] = i
+	}
+	isort.Sort(keys, mode, reverse)
+	out := make([]T, 0, len(items))
+	for _, k := range keys {
+		out = append(out, items[pos[k]])
+	}
+	return out
+}
+
 func (a *App) Run() {
+	if !a.useColor() {
+		a.Writer = render.WithoutColor(a.Writer)
+	}
+	if a.Config.CheckOverrides {
+		a.checkOverrides()
+		return
+	}
+	a.logOverrideProblems(a.OverrideErr)
+	if a.Config.IconsCommand != nil {
+		a.runIcons()
+		return
+	}
 	args := a.GetArguments()
 
 	if len(args.Files) > 0 {
@@ -124,16 +222,42 @@ func (a *App) Run() {
 	} else {
 		a.processDirsNonRecursively(args.Dirs)
 	}
+	if a.Config.Audit {
+		a.audit.summarize(a.Writer)
+	}
+}
+
+// useColor resolves --color; auto colors only when stdout is a terminal.
+func (a *App) useColor() bool {
+	switch a.Config.Color {
+	case cli.ColorNever:
+		return false
+	case cli.ColorAuto:
+		return term.IsTerminal(int(os.Stdout.Fd()))
+	}
+	return true
+}
+
+// background resolves --background; auto reads $COLORFGBG.
+func (a *App) background() icons.Background {
+	switch a.Config.Background {
+	case cli.BackgroundDark:
+		return icons.BackgroundDark
+	case cli.BackgroundLight:
+		return icons.BackgroundLight
+	}
+	return icons.DetectBackground(os.Getenv("COLORFGBG"))
 }
 
 func (a *App) processDirsRecursively(dirs []DirectoryEntry) {
 	currentAbs, _ := a.FS.Abs(".")
-	openDirIcon := OpenDirIconString(!a.Config.DisableIcon)
+	openDirIcon := OpenDirIconString(!a.Config.DisableIcon, a.Config.IconSet)
 
 	for i, dirEntry := range dirs {
 		if i > 0 {
 			fmt.Fprintln(a.Writer)
 		}
+		a.audit.root = a.auditRoot(dirEntry.AbsPath)
 
 		relName := dirEntry.Name()
 		if rel, err := a.FS.Rel(currentAbs, dirEntry.Name()); err == nil {
@@ -148,9 +272,11 @@ func (a *App) processDirsRecursively(dirs []DirectoryEntry) {
 
 func (a *App) processDirsNonRecursively(dirs []DirectoryEntry) {
 	pName := len(dirs) > 1
-	openDirIcon := OpenDirIconString(!a.Config.DisableIcon)
+	openDirIcon := OpenDirIconString(!a.Config.DisableIcon, a.Config.IconSet)
 
 	for i, dirEntry := range dirs {
+		a.project = a.projectFor(dirEntry.AbsPath)
+		a.audit.root = a.auditRoot(dirEntry.AbsPath)
 		if pName {
 			fmt.Fprintf(a.Writer, "%s:\n", openDirIcon+dirEntry.Name())
 		}
@@ -177,10 +303,18 @@ func (a *App) recurseDirectory(start *DirectoryEntry, startingAbsolutePath strin
 		current := stack[idx]
 		stack = stack[:idx]
 
+		ancestors, ok := a.enterFrame(current)
+		if !ok {
+			continue
+		}
+
 		if current.header != "" {
-			fmt.Fprintf(a.Writer, "\n%s:\n", OpenDirIconString(!a.Config.DisableIcon)+current.header)
+			fmt.Fprintf(a.Writer, "\n%s:\n", OpenDirIconString(!a.Config.DisableIcon, a.Config.IconSet)+current.header)
 		}
 
+		// each directory is listed under its own project files; the audit
+		// still judges links against the whole listed tree
+		a.project = a.projectFor(current.entry.AbsPath)
 		d, err := a.ProcessDirectory(current.entry)
 		current.entry.Close()
 		if err != nil {
@@ -195,16 +329,38 @@ func (a *App) recurseDirectory(start *DirectoryEntry, startingAbsolutePath strin
 		}
 
 		sort.Strings(d.Dirs)
-		stack = a.pushSubdirFrames(stack, current.entry, d.Dirs, startingAbsolutePath)
+		stack = a.pushSubdirFrames(stack, current.entry, ancestors, d.Dirs, startingAbsolutePath)
+	}
+}
+
+// enterFrame returns the ancestors of frame's subdirectories. With -L it
+// refuses, like GNU ls, a directory that is one of its own ancestors.
+func (a *App) enterFrame(frame *RecursiveLookupFrame) ([]string, bool) {
+	if a.Config.Dereference != cli.DereferenceAll {
+		return nil, true
+	}
+	resolved, err := a.FS.EvalSymlinks(frame.entry.AbsPath)
+	if err != nil {
+		resolved = frame.entry.AbsPath
 	}
+	for _, seen := range frame.ancestors {
+		if seen == resolved {
+			a.Logger.Printf("%s: not listing already-listed directory\n", frame.header)
+			a.ExitCode.SetSerious()
+			frame.entry.Close()
+			return nil, false
+		}
+	}
+	return append(frame.ancestors[:len(frame.ancestors):len(frame.ancestors)], resolved), true
 }
 
-func (a *App) pushSubdirFrames(stack []*RecursiveLookupFrame, parent *DirectoryEntry, dirs []string, startingAbsolutePath string) []*RecursiveLookupFrame {
+func (a *App) pushSubdirFrames(stack []*RecursiveLookupFrame, parent *DirectoryEntry, ancestors []string, dirs []string, startingAbsolutePath string) []*RecursiveLookupFrame {
 	for i := len(dirs) - 1; i >= 0; i-- {
 		frame := a.openSubdirFrame(parent, dirs[i], startingAbsolutePath)
 		if frame == nil {
 			continue
 		}
+		frame.ancestors = ancestors
 		stack = append(stack, frame)
 	}
 	return stack
@@ -241,6 +397,7 @@ func (a *App) ProcessFiles(files []FileEntry) *Directory {
 	isLong := a.Config.LongListingMode != cli.LongListingNone
 
 	for _, fileEntry := range files {
+		a.audit.root = a.auditRoot(a.FS.Dir(fileEntry.AbsPath))
 		entry := a.buildEntry(fileEntry.AbsPath, fileEntry.Info, isLong)
 		t.Files = append(t.Files, entry)
 	}
@@ -288,6 +445,9 @@ func (a *App) populateDirectory(d *DirectoryEntry, dirStat fs.FileInfo) (*Direct
 		if !showHidden && strings.HasPrefix(name, ".") {
 			continue
 		}
+		if a.ignored(name) || a.hidden(name) {
+			continue
+		}
 		a.appendChildEntry(t, d, de, gitRepoStatus, isLong)
 	}
 
@@ -297,6 +457,20 @@ func (a *App) populateDirectory(d *DirectoryEntry, dirStat fs.FileInfo) (*Direct
 	return t, err
 }
 
+// ignored reports whether name matches one of the -I patterns.
+func (a *App) ignored(name string) bool {
+	return matchAny(a.Config.IgnorePatterns, name)
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *App) maybeAttachSelfEntry(t *Directory, d *DirectoryEntry, dirStat fs.FileInfo, isLong bool) {
 	if a.Config.AllMode != cli.IncludeAll && !a.Config.Directory {
 		return
@@ -320,6 +494,13 @@ func (a *App) appendChildEntry(t *Directory, d *DirectoryEntry, de fs.DirEntry,
 		a.Logger.Printf(cannotAccessFmt, fullpath, infoErr)
 		a.ExitCode.SetMinor()
 	}
+	isDir := de.IsDir()
+	if fi != nil && fi.Mode()&iofs.ModeSymlink != 0 && a.Config.Dereference == cli.DereferenceAll {
+		// -L: show the target; a broken link stays a link
+		if target, err := a.FS.Stat(fullpath); err == nil {
+			fi, isDir = target, target.IsDir()
+		}
+	}
 
 	entry := a.buildEntry(fullpath, fi, isLong)
 
@@ -335,7 +516,8 @@ func (a *App) appendChildEntry(t *Directory, d *DirectoryEntry, de fs.DirEntry,
 	}
 
 	t.Files = append(t.Files, entry)
-	if de.IsDir() {
+	// -x lists mount points but does not descend into them
+	if isDir && !(a.Config.OneFileSystem && entry.MountPoint) {
 		t.Dirs = append(t.Dirs, name+"/")
 	}
 }
@@ -347,7 +529,8 @@ func (a *App) fillMissingInfo(entry *inspect.InspectedEntry, de fs.DirEntry) {
 		entry.Indicator = "@"
 	}
 	if !a.Config.DisableIcon {
-		entry.Icon = icons.ResolveWith(a.IconOverride, entry.Base, entry.Ext, entry.Indicator)
+		entry.Icon = icons.ResolveIn(a.iconOverride(), a.FS.Dir(entry.AbsPath), entry.Base, entry.Ext, entry.Indicator)
+		a.debugIcon(entry.AbsPath, entry)
 	}
 }
 
@@ -372,35 +555,81 @@ func (a *App) appendDotEntries(t *Directory, d *DirectoryEntry, isLong bool) {
 	t.Parent = parentEntry
 }
 
-// inspectorFor builds an Inspector for exactly the columns the current mode needs.
+// inspectorKey identifies the inspectors inspectorFor hands out.
+type inspectorKey struct {
+	long     bool
+	override *icons.Override
+}
+
+// inspectorFor returns an Inspector for exactly the columns the current
+// mode needs. Inspectors are kept for the run so their owner and group
+// lookups are cached across directories.
 func (a *App) inspectorFor(isLong bool) *inspect.Inspector {
+	key := inspectorKey{isLong, a.iconOverride()}
+	if insp, ok := a.inspectors[key]; ok {
+		return insp
+	}
 	showOwner := a.Config.LongListingMode == cli.LongListingDefault ||
 		a.Config.LongListingMode == cli.LongListingOwner
 	showGroup := !a.Config.NoGroup &&
 		(a.Config.LongListingMode == cli.LongListingDefault ||
 			a.Config.LongListingMode == cli.LongListingGroup)
-	return inspect.New(a.FS, inspect.IconResolverWith(a.IconOverride), inspect.Options{
+	insp := inspect.New(a.FS, inspect.IconResolverWith(key.override), inspect.Options{
 		Long:            isLong,
 		ShowOwner:       showOwner,
 		ShowGroup:       showGroup,
+		ShowAuthor:      a.Config.Author,
+		NumericIDs:      a.Config.NumericIDs,
 		ShowInode:       a.Config.ShowInodeNumber,
 		ShowBlocks:      a.Config.ShowBlockSize,
-		ResolveSymlinks: !a.Config.DisableIcon,
+		WantXAttr:       isLong,
+		ListXAttr:       a.Config.XAttr,
+		WantContext:     a.Config.Context,
+		WantFlags:       a.Config.Attributes,
+		Audit:           a.Config.Audit,
+		Mounts:          !a.Config.DisableIcon || a.Config.OneFileSystem,
+		FSType:          a.Config.FSType,
+		ResolveSymlinks: true, // also with -e, to mark broken links
+		LinkChain:       a.Config.LinkChain,
 		DisableIcon:     a.Config.DisableIcon,
+		Sniff:           a.Config.Sniff,
 	})
+	if a.inspectors == nil {
+		a.inspectors = make(map[inspectorKey]*inspect.Inspector)
+	}
+	a.inspectors[key] = insp
+	return insp
 }
 
 // buildEntry inspects fullPath. When fi is nil, returns a stub entry with just the name set.
 func (a *App) buildEntry(fullPath string, fi fs.FileInfo, isLong bool) *inspect.InspectedEntry {
 	insp := a.inspectorFor(isLong)
-	return insp.Inspect(fullPath, fi)
+	entry := insp.Inspect(fullPath, fi)
+	a.debugIcon(fullPath, entry)
+	if a.Config.Audit {
+		entry.Warnings = insp.Audit(entry, a.auditScope())
+		a.audit.add(entry.Warnings)
+	}
+	return entry
+}
+
+// debugIcon reports, with --debug-icons, which file supplied entry's icon.
+func (a *App) debugIcon(fullPath string, entry *inspect.InspectedEntry) {
+	if !a.Config.DebugIcons || entry.Icon == nil {
+		return
+	}
+	source := "built-in icons"
+	if from, ok := a.iconOverride().Provenance(a.FS.Dir(entry.AbsPath), entry.Base, entry.Ext, entry.Indicator); ok {
+		source = from.String()
+	}
+	a.Logger.Printf("%s: icon from %s\n", fullPath, source)
 }
 
 func (a *App) PrintDirectory(d *Directory) {
 	if d == nil {
 		return
 	}
-	isort.Sort(d.Files, a.Config.SortMode, a.Config.Reverse)
+	isort.Sort(d.Files, a.sortMode(), a.Config.Reverse)
 	render.Render(a.Writer, d.Files, render.Options{
 		Mode:          a.renderMode(),
 		ShowIcon:      !a.Config.DisableIcon,
@@ -408,6 +637,18 @@ func (a *App) PrintDirectory(d *Directory) {
 		ShowBlocks:    a.Config.ShowBlockSize,
 		HumanReadable: a.Config.HumanReadable,
 		TimeFormatter: a.Config.TimeFormatter,
+		TerminalWidth: a.Config.Width,
+		Glyphs:        a.Config.IconSet,
+		Background:    a.background(),
+		XAttrs:        a.Config.XAttr,
+		XAttrValues:   a.Config.XAttrValues,
+		ShowContext:   a.Config.Context,
+		ShowFlags:     a.Config.Attributes,
+		ShowFSType:    a.Config.FSType,
+		Audit:         a.Config.Audit,
+
+		OctalPermissions: a.Config.OctalPermissions,
+		PermissionColors: a.Config.PermissionColors,
 	})
 }
 
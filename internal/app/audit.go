@@ -0,0 +1,70 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/canta2899/logo-ls/internal/inspect"
+)
+
+// auditTally counts the --audit findings of a run.
+type auditTally struct {
+	root   string // the listed tree, for inspect.AuditScope
+	counts map[string]int
+	files  int // files with at least one finding
+}
+
+func (t *auditTally) add(warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	if t.counts == nil {
+		t.counts = make(map[string]int)
+	}
+	t.files++
+	for _, w := range warnings {
+		t.counts[w]++
+	}
+}
+
+// summarize writes the findings by kind after the listing.
+func (t *auditTally) summarize(w io.Writer) {
+	if t.files == 0 {
+		fmt.Fprintln(w, "\naudit: no findings")
+		return
+	}
+	total := 0
+	for _, n := range t.counts {
+		total += n
+	}
+	fmt.Fprintf(w, "\naudit: %d %s in %d %s\n", total, plural(total, "finding"), t.files, plural(t.files, "file"))
+	for _, kind := range inspect.AuditWarnings {
+		if n := t.counts[kind]; n > 0 {
+			fmt.Fprintf(w, "  %-18s %d\n", kind, n)
+		}
+	}
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+	return word + "s"
+}
+
+// auditScope returns what the entries being listed are audited against.
+func (a *App) auditScope() inspect.AuditScope {
+	home, _ := os.UserHomeDir()
+	return inspect.AuditScope{Root: a.audit.root, Home: home, UID: os.Getuid()}
+}
+
+// auditRoot returns the real path of the listed directory dir, which
+// resolved link destinations are judged against, or dir itself when it
+// cannot be resolved.
+func (a *App) auditRoot(dir string) string {
+	if real, err := a.FS.EvalSymlinks(dir); err == nil {
+		return real
+	}
+	return dir
+}
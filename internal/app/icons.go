@@ -0,0 +1,125 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/canta2899/logo-ls/internal/icons"
+	"github.com/canta2899/logo-ls/internal/inspect"
+	"github.com/canta2899/logo-ls/internal/render"
+)
+
+// runIcons runs the "logo-ls icons" command in Config.IconsCommand.
+func (a *App) runIcons() {
+	var err error
+	switch words := a.Config.IconsCommand; words[0] {
+	case "check":
+		a.checkIcons()
+	case "list":
+		err = render.WriteMappings(a.Writer, icons.Mappings(a.IconOverride), a.Config.IconSet, a.background())
+	case "which":
+		a.whichIcon(words[1])
+	case "search":
+		err = render.WriteMappings(a.Writer, icons.Search(a.IconOverride, words[1]), a.Config.IconSet, a.background())
+	}
+	if err != nil {
+		panic(err)
+	}
+}
+
+// whichIcon explains the icon of the file at argPath, which need not exist.
+// Existing files are classified like a listing would (directory,
+// executable, device, setuid, ...); a trailing slash marks a missing one as a directory. The
+// project files of its directory apply.
+func (a *App) whichIcon(argPath string) {
+	abs, err := a.FS.Abs(argPath)
+	if err != nil {
+		a.Logger.Printf("cannot get absolute path for %q: %v\n", argPath, err)
+		a.ExitCode.SetSerious()
+		return
+	}
+	dir, name := a.FS.Dir(abs), a.FS.Base(abs)
+	indicator, special := "", ""
+	if fi, err := a.FS.Stat(abs); err == nil {
+		special = inspect.SpecialIcon(fi.Mode())
+		switch {
+		case fi.IsDir():
+			indicator = "/"
+		case fi.Mode()&0o111 != 0:
+			indicator = "*"
+		}
+	} else if strings.HasSuffix(argPath, "/") {
+		indicator = "/"
+	}
+	a.project = a.projectFor(dir)
+	base, ext := inspect.SplitNameExt(name, a.FS)
+	x := icons.ExplainSpecial(special)
+	if special == "" {
+		x = icons.Explain(a.iconOverride(), dir, base, ext, indicator)
+	}
+	if err := render.WriteExplanation(a.Writer, argPath, x, a.Config.IconSet, a.background()); err != nil {
+		panic(err)
+	}
+}
+
+// checkIcons reports the built-in and override glyphs missing from Nerd
+// Fonts v3, one per line; any issue makes the exit code CodeMinor.
+func (a *App) checkIcons() {
+	issues := append(icons.CheckBuiltin(), a.IconOverride.Check()...)
+	for _, issue := range issues {
+		fmt.Fprintln(a.Writer, issue)
+	}
+	if len(issues) > 0 {
+		a.ExitCode.SetMinor()
+		return
+	}
+	fmt.Fprintln(a.Writer, "all glyphs are part of Nerd Fonts v3")
+}
+
+// checkOverrides reports the problems found in the override files, for
+// --check-overrides; any problem makes the exit code CodeMinor.
+func (a *App) checkOverrides() {
+	a.logProblems("", a.OverrideErr)
+	if a.OverrideErr != nil {
+		a.ExitCode.SetMinor()
+	}
+}
+
+// logOverrideProblems logs the problems icons.LoadOverrides reported,
+// telling the entries that were skipped, and the keys ignored around the
+// entries kept, from the files left out whole.
+func (a *App) logOverrideProblems(err error) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			a.logOverrideProblems(err)
+		}
+		return
+	}
+	var problem *icons.Problem
+	if errors.As(err, &problem) {
+		a.logEntryProblem(problem, "icon overrides: skipping bad entry: ", "icon overrides: ignoring: ")
+		return
+	}
+	a.logProblems("icon overrides: skipping file: ", err)
+}
+
+// logEntryProblem logs problem after skipped when its entry was left out,
+// or after ignored when the entry was kept.
+func (a *App) logEntryProblem(problem *icons.Problem, skipped, ignored string) {
+	if problem.Skipped {
+		a.logProblems(skipped, problem)
+		return
+	}
+	a.logProblems(ignored, problem)
+}
+
+// logProblems logs each line of err, if any, after prefix.
+func (a *App) logProblems(prefix string, err error) {
+	if err == nil {
+		return
+	}
+	for msg := range strings.SplitSeq(err.Error(), "\n") {
+		a.Logger.Printf("%s%s\n", prefix, msg)
+	}
+}
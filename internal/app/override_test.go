@@ -2,10 +2,12 @@ package app
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/canta2899/logo-ls/internal/cli"
@@ -108,3 +110,80 @@ func TestAppNoIconOverrideMatchesDefault(t *testing.T) {
 		}
 	}
 }
+
+// TestAppIconsCheck verifies "logo-ls icons check" reports override glyphs
+// missing from Nerd Fonts v3 and sets a minor exit code.
+func TestAppIconsCheck(t *testing.T) {
+	conf := &cli.Config{IconsCommand: []string{"check"}, Color: cli.ColorNever}
+	var out bytes.Buffer
+	appInstance := newTestApp(conf, log.New(io.Discard, "", 0), &out)
+	appInstance.Run()
+	if appInstance.ExitCode != cli.CodeOk || !strings.Contains(out.String(), "all glyphs") {
+		t.Fatalf("expected the built-in icons to pass, got %d:\n%s", appInstance.ExitCode, out.String())
+	}
+
+	ov, err := icons.ParseOverrides("icons.yaml", []byte(`
+extensions:
+  swf:
+    codepoint: "U+FB25"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	appInstance = newTestApp(conf, log.New(io.Discard, "", 0), &out)
+	appInstance.IconOverride = ov
+	appInstance.Run()
+	if appInstance.ExitCode != cli.CodeMinor {
+		t.Errorf("expected exit code %d, got %d", cli.CodeMinor, appInstance.ExitCode)
+	}
+	if want := "icons.yaml (extensions: swf): U+FB25 was removed in Nerd Fonts v3; use U+F0626\n"; out.String() != want {
+		t.Errorf("expected %q, got %q", want, out.String())
+	}
+}
+
+// TestAppCheckOverrides verifies --check-overrides logs every problem and
+// exits with a minor code, without listing.
+func TestAppCheckOverrides(t *testing.T) {
+	ov, problems := icons.ParseOverrides("icons.yaml", []byte("extensions:\n  go: {color: nope}\n  rs: {glyph: R}\n"))
+	conf := &cli.Config{CheckOverrides: true, FileList: []string{"."}}
+	var out, logs bytes.Buffer
+	appInstance := newTestApp(conf, log.New(&logs, "", 0), &out)
+	appInstance.IconOverride, appInstance.OverrideErr = ov, problems
+	appInstance.Run()
+	if appInstance.ExitCode != cli.CodeMinor {
+		t.Errorf("expected exit code %d, got %d", cli.CodeMinor, appInstance.ExitCode)
+	}
+	if want := "icons.yaml:2:15: extensions: \"go\": invalid color \"nope\": expected #RGB, #RRGGBB, rgb(), hsl(), a CSS color name, xterm:N, ansi:NAME or @icon\n"; logs.String() != want {
+		t.Errorf("expected %q, got %q", want, logs.String())
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected no listing, got %q", out.String())
+	}
+
+	appInstance = newTestApp(conf, log.New(&logs, "", 0), &out)
+	appInstance.Run()
+	if appInstance.ExitCode != cli.CodeOk {
+		t.Errorf("expected exit code %d without problems, got %d", cli.CodeOk, appInstance.ExitCode)
+	}
+}
+
+// TestAppLogsOverrideProblems verifies the problems are logged at startup
+// as skipped entries, ignored keys, or skipped files when a file could not
+// be parsed.
+func TestAppLogsOverrideProblems(t *testing.T) {
+	_, entryErr := icons.ParseOverrides("icons.yaml", []byte("extensions:\n  go: {color: nope}\n  rs: {glyph: R, colour: red}\n"))
+	_, fileErr := icons.ParseOverrides("broken.yaml", []byte("extensions: [\n"))
+	conf := &cli.Config{FileList: []string{t.TempDir()}, DisableIcon: true}
+	var out, logs bytes.Buffer
+	appInstance := newTestApp(conf, log.New(&logs, "", 0), &out)
+	appInstance.OverrideErr = errors.Join(entryErr, fileErr)
+	appInstance.Run()
+	got := strings.Split(strings.TrimSuffix(logs.String(), "\n"), "\n")
+	if len(got) != 3 ||
+		!strings.HasPrefix(got[0], "icon overrides: skipping bad entry: icons.yaml:2:15: ") ||
+		got[1] != `icon overrides: ignoring: icons.yaml:3:18: extensions: "rs": unknown key "colour"` ||
+		!strings.HasPrefix(got[2], "icon overrides: skipping file: parse broken.yaml: ") {
+		t.Errorf("unexpected log %q", logs.String())
+	}
+}
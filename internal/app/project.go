@@ -0,0 +1,165 @@
+package app
+
+import (
+	"errors"
+	"os"
+
+	"github.com/canta2899/logo-ls/internal/cli"
+	"github.com/canta2899/logo-ls/internal/icons"
+	"github.com/canta2899/logo-ls/internal/inspect/platform"
+	"github.com/canta2899/logo-ls/pkg/fs"
+	"gopkg.in/yaml.v3"
+)
+
+// projectFileName is the per-project settings file. Besides the icon
+// sections of the override file it accepts:
+//
+//	hide: ["*.gen.go"]  # like -I, but -a and -A show them again
+//	sort: time          # used when no sort option was given
+const projectFileName = ".logo-ls.yaml"
+
+// project holds the settings of the .logo-ls.yaml files above a listed
+// directory.
+type project struct {
+	override *icons.Override // the user overrides with the project files layered on top
+	hide     []string
+	sort     cli.SortMode
+	hasSort  bool
+}
+
+type projectOptions struct {
+	Hide []string `yaml:"hide"`
+	Sort string   `yaml:"sort"`
+}
+
+// projectFor returns the project settings that apply to the listed
+// directory dir, or nil when there are none.
+func (a *App) projectFor(dir string) *project {
+	if a.Config.NoProject {
+		return nil
+	}
+	if p, ok := a.projects[dir]; ok {
+		return p
+	}
+	var p *project
+	if paths := a.projectFiles(dir); len(paths) > 0 {
+		p = &project{override: a.IconOverride}
+		// outermost first, so the files nearer to dir win
+		for i := len(paths) - 1; i >= 0; i-- {
+			a.loadProjectFile(p, paths[i])
+		}
+	}
+	if a.projects == nil {
+		a.projects = map[string]*project{}
+	}
+	a.projects[dir] = p
+	return p
+}
+
+// projectFiles walks up from dir and returns every project file found,
+// nearest first. The walk stops at the git root or $HOME, whichever comes
+// first; a directory under neither has no project files, so a file left in
+// a shared directory such as /tmp never applies. Files owned by someone
+// other than the user or root are skipped for the same reason.
+func (a *App) projectFiles(dir string) []string {
+	home, _ := os.UserHomeDir()
+	var paths []string
+	for {
+		candidate := a.FS.Join(dir, projectFileName)
+		if fi, err := a.FS.Stat(candidate); err == nil && !fi.IsDir() {
+			if ownedByUser(fi) {
+				paths = append(paths, candidate)
+			} else {
+				a.Logger.Printf("ignoring project file %s: not owned by you or root\n", candidate)
+			}
+		}
+		if dir == home {
+			return paths
+		}
+		if _, err := a.FS.Lstat(a.FS.Join(dir, ".git")); err == nil {
+			return paths
+		}
+		parent := a.FS.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// ownedByUser reports whether fi belongs to the current user or root.
+// Windows reports neither owner, and every file passes.
+func ownedByUser(fi fs.FileInfo) bool {
+	uid := platform.NewReader().Read("", fi, platform.Options{}).UID
+	return uid == 0 || int(uid) == os.Getuid()
+}
+
+// loadProjectFile layers the file at path over p. Like in the user
+// override files, invalid entries are reported and skipped, and a file
+// that is not valid YAML is left out.
+func (a *App) loadProjectFile(p *project, path string) {
+	data, err := fs.ReadFile(a.FS, path)
+	if err != nil {
+		a.Logger.Printf("ignoring project file: %v\n", err)
+		return
+	}
+	ov, err := icons.ParseOverrides(path, data, "hide", "sort")
+	var problem *icons.Problem
+	if err != nil && !errors.As(err, &problem) {
+		a.Logger.Printf("ignoring project file: %v\n", err)
+		return
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			if errors.As(err, &problem) {
+				a.logEntryProblem(problem, "skipping project file entry: ", "ignoring in project file: ")
+			}
+		}
+	}
+	p.override = p.override.Layer(ov)
+	var opts projectOptions
+	if err := yaml.Unmarshal(data, &opts); err != nil {
+		a.Logger.Printf("ignoring project file options: parse %s: %v\n", path, err)
+		return
+	}
+	p.hide = append(p.hide, opts.Hide...)
+	if opts.Sort != "" {
+		mode, ok := cli.SortModeFor(opts.Sort)
+		if !ok {
+			a.Logger.Printf("ignoring sort %q in %s\n", opts.Sort, path)
+			return
+		}
+		p.sort, p.hasSort = mode, true
+	}
+}
+
+// iconOverride returns the overrides for the directory being listed.
+func (a *App) iconOverride() *icons.Override {
+	if a.project != nil {
+		return a.project.override
+	}
+	return a.IconOverride
+}
+
+// sortMode returns the active sort mode for the directory being listed.
+func (a *App) sortMode() cli.SortMode {
+	return a.sortModeIn(a.project)
+}
+
+// sortModeIn returns the sort mode under project p: a project's default
+// sort applies only when no option picked one.
+func (a *App) sortModeIn(p *project) cli.SortMode {
+	if p != nil && p.hasSort && !a.Config.SortChosen {
+		return p.sort
+	}
+	return a.Config.SortMode
+}
+
+// hidden reports whether name matches one of the project's hide patterns.
+// Like --hide in GNU ls, -a and -A list them anyway.
+func (a *App) hidden(name string) bool {
+	if a.project == nil || a.Config.AllMode != cli.IncludeDefault {
+		return false
+	}
+	return matchAny(a.project.hide, name)
+}
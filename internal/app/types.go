@@ -6,13 +6,13 @@ import (
 	"github.com/canta2899/logo-ls/pkg/fs"
 )
 
-// OpenDirIconString returns the colored open-directory glyph for a directory banner, or "" when icons are disabled.
-func OpenDirIconString(showIcon bool) string {
+// OpenDirIconString returns the colored open-directory glyph from set for a directory banner, or "" when icons are disabled.
+func OpenDirIconString(showIcon bool, set icons.GlyphSet) string {
 	if !showIcon {
 		return ""
 	}
 	d := icons.IconDef["diropen"]
-	return d.GetColor() + d.GetGlyph() + "\033[0m" + " "
+	return d.GetColor() + d.GlyphIn(set) + "\033[0m" + " "
 }
 
 // FileEntry is a non-directory argument (e.g. logo-ls file.txt).
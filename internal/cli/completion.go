@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Shells lists the shells WriteCompletion can generate scripts for.
+var Shells = []string{"bash", "zsh", "fish", "powershell"}
+
+// WriteCompletion writes a completion script for shell to w. The script is
+// generated from p.Flags so it never drifts from the options the parser
+// actually accepts.
+func WriteCompletion(w io.Writer, p *Parser, shell string) error {
+	bw := bufio.NewWriter(w)
+	switch shell {
+	case "bash":
+		writeBashCompletion(bw, p)
+	case "zsh":
+		writeZshCompletion(bw, p)
+	case "fish":
+		writeFishCompletion(bw, p)
+	case "powershell":
+		writePowerShellCompletion(bw, p)
+	default:
+		return fmt.Errorf("unsupported shell %q (valid: %s)", shell, strings.Join(Shells, ", "))
+	}
+	return bw.Flush()
+}
+
+// optionNames returns every spelling of f as typed on the command line.
+func optionNames(f *Flag) []string {
+	var names []string
+	if f.Short != 0 {
+		names = append(names, "-"+string(f.Short))
+	}
+	if f.Long != "" {
+		names = append(names, "--"+f.Long)
+	}
+	return names
+}
+
+func takesValue(f *Flag) bool { return f.Type != FlagBool }
+
+func writeBashCompletion(w io.Writer, p *Parser) {
+	var words []string
+	for _, f := range p.Flags {
+		words = append(words, optionNames(f)...)
+	}
+
+	fmt.Fprintln(w, "# bash completion for logo-ls")
+	fmt.Fprintln(w, "# generated by `logo-ls --completion bash`; do not edit.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "_logo_ls() {")
+	fmt.Fprintln(w, `    local cur prev`)
+	fmt.Fprintln(w, `    cur="${COMP_WORDS[COMP_CWORD]}"`)
+	fmt.Fprintln(w, `    prev="${COMP_WORDS[COMP_CWORD-1]}"`)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `    # "--opt=value" is split into "--opt" "=" "value" by COMP_WORDBREAKS.`)
+	fmt.Fprintln(w, `    if [[ "$cur" == "=" ]]; then`)
+	fmt.Fprintln(w, `        prev="${COMP_WORDS[COMP_CWORD-1]}"`)
+	fmt.Fprintln(w, `        cur=""`)
+	fmt.Fprintln(w, `    elif [[ "$prev" == "=" ]]; then`)
+	fmt.Fprintln(w, `        prev="${COMP_WORDS[COMP_CWORD-2]}"`)
+	fmt.Fprintln(w, `    fi`)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `    case "$prev" in`)
+	for _, f := range p.Flags {
+		if !takesValue(f) {
+			continue
+		}
+		fmt.Fprintf(w, "        %s)\n", strings.Join(optionNames(f), "|"))
+		switch {
+		case len(f.Values) > 0:
+			fmt.Fprintf(w, "            COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(f.Values, " "))
+		case f.Hint == HintFile:
+			fmt.Fprintln(w, `            COMPREPLY=($(compgen -f -- "$cur"))`)
+		default:
+			fmt.Fprintln(w, `            COMPREPLY=()`)
+		}
+		fmt.Fprintln(w, "            return")
+		fmt.Fprintln(w, "            ;;")
+	}
+	fmt.Fprintln(w, `    esac`)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `    if [[ "$cur" == -* ]]; then`)
+	fmt.Fprintf(w, "        COMPREPLY=($(compgen -W %q -- \"$cur\"))\n", strings.Join(words, " "))
+	fmt.Fprintln(w, `        return`)
+	fmt.Fprintln(w, `    fi`)
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `    COMPREPLY=($(compgen -f -- "$cur"))`)
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "complete -o filenames -F _logo_ls logo-ls")
+}
+
+// zshEscape escapes a description for use inside a single-quoted
+// _arguments spec, where brackets and colons are significant.
+func zshEscape(s string) string {
+	r := strings.NewReplacer(`'`, `'\''`, `[`, `\[`, `]`, `\]`, `:`, `\:`)
+	return r.Replace(s)
+}
+
+func writeZshCompletion(w io.Writer, p *Parser) {
+	fmt.Fprintln(w, "#compdef logo-ls")
+	fmt.Fprintln(w, "# zsh completion for logo-ls")
+	fmt.Fprintln(w, "# generated by `logo-ls --completion zsh`; do not edit.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "_logo-ls() {")
+	fmt.Fprintln(w, "  _arguments -s -S \\")
+	for _, f := range p.Flags {
+		names := optionNames(f)
+		exclusion := ""
+		switch {
+		case f.Type == FlagStringList:
+			exclusion = "*"
+		case len(names) > 1:
+			exclusion = "(" + strings.Join(names, " ") + ")"
+		}
+		for _, name := range names {
+			spec := exclusion + name
+			if takesValue(f) {
+				if strings.HasPrefix(name, "--") {
+					spec += "="
+				} else {
+					spec += "+"
+				}
+			}
+			spec += "[" + zshEscape(f.Description) + "]"
+			if takesValue(f) {
+				switch {
+				case len(f.Values) > 0:
+					spec += ":value:(" + strings.Join(f.Values, " ") + ")"
+				case f.Hint == HintFile:
+					spec += ":file:_files"
+				default:
+					spec += ":value: "
+				}
+			}
+			fmt.Fprintf(w, "    '%s' \\\n", spec)
+		}
+	}
+	fmt.Fprintln(w, "    '*:file:_files'")
+	fmt.Fprintln(w, "}")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `_logo-ls "$@"`)
+}
+
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func writeFishCompletion(w io.Writer, p *Parser) {
+	fmt.Fprintln(w, "# fish completion for logo-ls")
+	fmt.Fprintln(w, "# generated by `logo-ls --completion fish`; do not edit.")
+	fmt.Fprintln(w)
+	for _, f := range p.Flags {
+		line := "complete -c logo-ls"
+		if f.Short != 0 {
+			line += " -s " + fishQuote(string(f.Short))
+		}
+		if f.Long != "" {
+			line += " -l " + f.Long
+		}
+		if takesValue(f) {
+			switch {
+			case len(f.Values) > 0:
+				line += " -x -a " + fishQuote(strings.Join(f.Values, " "))
+			case f.Hint == HintFile:
+				line += " -r -F"
+			default:
+				line += " -x"
+			}
+		}
+		line += " -d " + fishQuote(f.Description)
+		fmt.Fprintln(w, line)
+	}
+}
+
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func writePowerShellCompletion(w io.Writer, p *Parser) {
+	fmt.Fprintln(w, "# PowerShell completion for logo-ls")
+	fmt.Fprintln(w, "# generated by `logo-ls --completion powershell`; do not edit.")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "using namespace System.Management.Automation")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Register-ArgumentCompleter -Native -CommandName 'logo-ls' -ScriptBlock {")
+	fmt.Fprintln(w, "    param($wordToComplete, $commandAst, $cursorPosition)")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "    $flags = @(")
+	for _, f := range p.Flags {
+		for _, name := range optionNames(f) {
+			fmt.Fprintf(w, "        @{ Name = %s; Description = %s }\n", psQuote(name), psQuote(f.Description))
+		}
+	}
+	fmt.Fprintln(w, "    )")
+	fmt.Fprintln(w, "    $values = @{")
+	for _, f := range p.Flags {
+		if len(f.Values) == 0 {
+			continue
+		}
+		quoted := make([]string, len(f.Values))
+		for i, v := range f.Values {
+			quoted[i] = psQuote(v)
+		}
+		fmt.Fprintf(w, "        %s = @(%s)\n", psQuote("--"+f.Long), strings.Join(quoted, ", "))
+	}
+	fmt.Fprintln(w, "    }")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "    $elements = @($commandAst.CommandElements | ForEach-Object { $_.ToString() })")
+	fmt.Fprintln(w, "    $prev = if ($wordToComplete) { $elements[-2] } else { $elements[-1] }")
+	fmt.Fprintln(w, "    $option, $inline = $wordToComplete -split '=', 2")
+	fmt.Fprintln(w, "    if ($null -ne $inline -and $values.ContainsKey($option)) {")
+	fmt.Fprintln(w, "        $values[$option] | Where-Object { $_ -like \"$inline*\" } | ForEach-Object {")
+	fmt.Fprintln(w, "            [CompletionResult]::new(\"$option=$_\", $_, [CompletionResultType]::ParameterValue, $_)")
+	fmt.Fprintln(w, "        }")
+	fmt.Fprintln(w, "        return")
+	fmt.Fprintln(w, "    }")
+	fmt.Fprintln(w, "    if ($values.ContainsKey($prev)) {")
+	fmt.Fprintln(w, "        $values[$prev] | Where-Object { $_ -like \"$wordToComplete*\" } | ForEach-Object {")
+	fmt.Fprintln(w, "            [CompletionResult]::new($_, $_, [CompletionResultType]::ParameterValue, $_)")
+	fmt.Fprintln(w, "        }")
+	fmt.Fprintln(w, "        return")
+	fmt.Fprintln(w, "    }")
+	fmt.Fprintln(w, "    if ($wordToComplete.StartsWith('-')) {")
+	fmt.Fprintln(w, "        $flags | Where-Object { $_.Name.StartsWith($wordToComplete) } | ForEach-Object {")
+	fmt.Fprintln(w, "            [CompletionResult]::new($_.Name, $_.Name, [CompletionResultType]::ParameterName, $_.Description)")
+	fmt.Fprintln(w, "        }")
+	fmt.Fprintln(w, "    }")
+	fmt.Fprintln(w, "}")
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "regenerate golden files")
+
+// assertGolden compares got against testdata/<name>, rewriting it with -update.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatalf("mkdir testdata: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("write golden %s: %v", path, err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden %s: %v (run `go test ./internal/cli -update` to create)", path, err)
+	}
+	if !bytes.Equal(want, got) {
+		t.Errorf("golden %s mismatch\n--- want ---\n%s\n--- got ---\n%s", name, want, got)
+	}
+}
+
+// Verifies that every supported shell renders a script matching its golden.
+func TestCompletionGolden(t *testing.T) {
+	for _, shell := range Shells {
+		t.Run(shell, func(t *testing.T) {
+			_, opt, err := BuildConfig([]string{"app", "--completion", shell})
+			var req *CompletionRequest
+			if !errors.As(err, &req) || req.Shell != shell {
+				t.Fatalf("expected CompletionRequest for %s, got %v", shell, err)
+			}
+			var buf bytes.Buffer
+			if err := WriteCompletion(&buf, opt, shell); err != nil {
+				t.Fatalf("WriteCompletion: %v", err)
+			}
+			assertGolden(t, "completion."+shell, buf.Bytes())
+		})
+	}
+}
+
+// Verifies that an unknown shell is rejected by the parser.
+func TestCompletionUnknownShell(t *testing.T) {
+	if _, _, err := BuildConfig([]string{"app", "--completion", "tcsh"}); err == nil {
+		t.Error("expected error for unsupported shell")
+	}
+}
+
+// Verifies that --sort selects the matching sort mode and rejects unknown words.
+func TestSortWord(t *testing.T) {
+	tests := []struct {
+		word     string
+		expected SortMode
+	}{
+		{"none", SortNone},
+		{"size", SortSize},
+		{"time", SortModTime},
+		{"version", SortNatural},
+		{"extension", SortExtension},
+		{"name", SortAlphabetical},
+	}
+	for _, tt := range tests {
+		cfg := parseArgs([]string{"app", "-t", "--sort=" + tt.word})
+		if cfg.SortMode != tt.expected {
+			t.Errorf("--sort=%s: expected %v, got %v", tt.word, tt.expected, cfg.SortMode)
+		}
+	}
+	if _, _, err := BuildConfig([]string{"app", "--sort", "bogus"}); err == nil {
+		t.Error("expected error for unknown sort word")
+	}
+}
+
+// Verifies that the man page and markdown reference match their goldens.
+func TestManPageGolden(t *testing.T) {
+	_, opt, err := BuildConfig([]string{"app", "--man"})
+	if !errors.Is(err, ErrManRequested) {
+		t.Fatalf("expected ErrManRequested, got %v", err)
+	}
+	var buf bytes.Buffer
+	if err := WriteManPage(&buf, opt); err != nil {
+		t.Fatalf("WriteManPage: %v", err)
+	}
+	assertGolden(t, "logo-ls.1", buf.Bytes())
+
+	_, opt, err = BuildConfig([]string{"app", "--markdown-reference"})
+	if !errors.Is(err, ErrReferenceRequested) {
+		t.Fatalf("expected ErrReferenceRequested, got %v", err)
+	}
+	buf.Reset()
+	if err := WriteMarkdownReference(&buf, opt); err != nil {
+		t.Fatalf("WriteMarkdownReference: %v", err)
+	}
+	assertGolden(t, "reference.md", buf.Bytes())
+}
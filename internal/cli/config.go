@@ -1,8 +1,11 @@
 // Package cli parses command-line flags and produces the Config that the
-// rest of logo-ls reads from. It owns nothing that touches the filesystem;
-// app.App wires the parsed config to an inspector + renderer.
+// rest of logo-ls reads from. Apart from reading the user config file
+// (see Defaults) it owns nothing that touches the filesystem; app.App wires
+// the parsed config to an inspector + renderer.
 package cli
 
+import "github.com/canta2899/logo-ls/internal/icons"
+
 type Config struct {
 	FileList          []string
 	AllMode           Include
@@ -20,7 +23,72 @@ type Config struct {
 	ShowBlockSize     bool
 	ShowInodeNumber   bool
 	NoIconOverride   bool
-	IconOverrideFile string
+	// IconOverrideFiles holds the --override-file paths, layered over the
+	// default override files in order.
+	IconOverrideFiles []string
+	// CheckOverrides reports the problems in the override files instead
+	// of listing.
+	CheckOverrides bool
+	// Width is the terminal width for the multi-column layout; 0 detects
+	// it from the terminal.
+	Width int
+	// IgnorePatterns holds the -I shell patterns; matching entries are
+	// not listed.
+	IgnorePatterns []string
+	Color          ColorMode
+	// SortChosen is set when an option picked SortMode, so a project's
+	// default sort must not replace it.
+	SortChosen bool
+	NoProject  bool
+	DebugIcons bool
+	// Sniff picks icons for files whose name matches nothing from their
+	// contents: shebangs and magic numbers.
+	Sniff bool
+	// IconSet selects the glyphs icons are drawn with.
+	IconSet icons.GlyphSet
+	// Background selects which of an override's dark and light colors
+	// icons are drawn in.
+	Background BackgroundMode
+	// Dereference selects the symlinks shown as the file they point to.
+	// Without -L, -H or --dereference-command-line-symlink-to-dir it is
+	// DereferenceCommandLineDirs, or DereferenceNone with -l or -d, like
+	// GNU ls.
+	Dereference DereferenceMode
+	// LinkChain shows the targets of every hop of multi-hop symlinks in
+	// the long listing.
+	LinkChain bool
+	// XAttr lists the extended attributes of each file under it in the
+	// long listing; XAttrValues also prints their values.
+	XAttr       bool
+	XAttrValues bool
+	// Context shows the SELinux security context of each file.
+	Context bool
+	// Attributes adds the chattr flags column to the long listing.
+	Attributes bool
+	// NumericIDs shows owners and groups as numeric ids (-n).
+	NumericIDs bool
+	// Author adds the author column to the long listing; on Unix the
+	// author is the owner.
+	Author bool
+	// OctalPermissions adds the permissions in octal to the long listing.
+	OctalPermissions bool
+	// PermissionColors colors read, write, execute and special letters of
+	// the permissions in the palette of the background.
+	PermissionColors bool
+	// Audit flags risky files in a column of their own and summarizes the
+	// findings after the listing.
+	Audit bool
+	// OneFileSystem keeps -R from descending into directories that are
+	// mount points (-x).
+	OneFileSystem bool
+	// FSType shows the type of the file system each file is on.
+	FSType bool
+	// DefaultsProblems holds why the config file, or some of its or
+	// $LOGO_LS_OPTIONS's entries, were skipped.
+	DefaultsProblems []error
+	// IconsCommand holds the words after "logo-ls icons", e.g. [check];
+	// nil when listing files.
+	IconsCommand []string
 }
 
 func NewConfig() *Config {
@@ -40,5 +108,6 @@ func NewConfig() *Config {
 		ShowInodeNumber: false,
 		TimeFormatter:   nil,
 		FileList:        []string{},
+		Dereference:     DereferenceCommandLineDirs,
 	}
 }
@@ -0,0 +1,261 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/canta2899/logo-ls/internal/icons"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	configFileName = "config.yaml"
+	// OptionsEnv names the environment variable holding default options.
+	OptionsEnv = "LOGO_LS_OPTIONS"
+)
+
+// Defaults holds the option sources applied before the command line, lowest
+// precedence first: the user config file, then $LOGO_LS_OPTIONS. The
+// command line is applied last and wins over both.
+type Defaults struct {
+	ConfigPath string // used in error messages
+	Config     []byte // contents of config.yaml; empty when absent
+	Env        string // value of $LOGO_LS_OPTIONS
+}
+
+// ConfigPath returns the location of the user config file, or "" when no
+// config directory can be determined.
+func ConfigPath() string {
+	dir := icons.ConfigDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, configFileName)
+}
+
+// ConfigPathDoc describes ConfigPath with environment variables left
+// unexpanded. Used to generate documentation.
+const ConfigPathDoc = "$XDG_CONFIG_HOME/logo-ls/" + configFileName
+
+// LoadDefaults reads the user config file and $LOGO_LS_OPTIONS. A missing
+// config file is not an error.
+func LoadDefaults() (Defaults, error) {
+	d := Defaults{ConfigPath: ConfigPath(), Env: os.Getenv(OptionsEnv)}
+	if d.ConfigPath == "" {
+		return d, nil
+	}
+	data, err := os.ReadFile(d.ConfigPath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return d, fmt.Errorf("read %s: %w", d.ConfigPath, err)
+	}
+	d.Config = data
+	return d, nil
+}
+
+// commandLineOnly lists the options that make no sense as a default because
+// they replace the listing altogether.
+var commandLineOnly = []string{"help", "version", "completion", "man", "markdown-reference", "no-config"}
+
+// configAliases maps config keys that are not option names to the option
+// they stand for, e.g. "long: true" for -l.
+var configAliases = map[string]string{"long": "-l"}
+
+// apply parses the config file and then $LOGO_LS_OPTIONS into p. Neither may
+// name operands or the options in commandLineOnly. A bad entry is skipped
+// and reported in the returned DefaultsErrors, so a typo in the defaults
+// never keeps the tool from running.
+func (d Defaults) apply(p *Parser) []error {
+	problems := applyConfigFile(p, d.ConfigPath, d.Config)
+	if strings.TrimSpace(d.Env) == "" {
+		return problems
+	}
+	args, err := splitOptions(d.Env)
+	if err != nil {
+		return append(problems, &DefaultsError{Source: OptionsEnv, Err: err})
+	}
+	for _, err := range parseDefault(p, args) {
+		problems = append(problems, &DefaultsError{Source: OptionsEnv, Err: err})
+	}
+	return problems
+}
+
+// applyConfigFile parses a config file whose top level maps long option
+// names, without the leading dashes, to their values:
+//
+//	sort: time
+//	git-status: true
+//	ignore: ["*.o", node_modules]
+//
+// Boolean options take true or false, repeatable options a single value or
+// a list, everything else a single value. "long: true" is accepted for
+// "format: long".
+func applyConfigFile(p *Parser, path string, data []byte) []error {
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return nil
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []error{&DefaultsError{Source: path, Err: err}}
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return []error{&DefaultsError{Source: path, Line: root.Line, Err: errors.New("expected a mapping of option names to values")}}
+	}
+	var problems []error
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		args, err := configArgs(p, key.Value, value)
+		if err != nil {
+			problems = append(problems, &DefaultsError{Source: path, Line: key.Line, Err: err})
+			continue
+		}
+		for _, err := range parseDefault(p, args) {
+			problems = append(problems, &DefaultsError{Source: path, Line: key.Line, Err: err})
+		}
+	}
+	return problems
+}
+
+// configArgs turns one config entry into the equivalent command-line
+// arguments. Names must be spelled out; abbreviations are only accepted on
+// the command line.
+func configArgs(p *Parser, name string, value *yaml.Node) ([]string, error) {
+	alias, isAlias := configAliases[name]
+	f := p.Lookup(name)
+	if f == nil && !isAlias {
+		return nil, &UnknownFlagError{Name: "--" + name}
+	}
+	display := "--" + name
+	if value.Tag == "!!null" {
+		return nil, nil
+	}
+	if isAlias {
+		switch {
+		case value.Kind != yaml.ScalarNode || value.Tag != "!!bool":
+			return nil, &InvalidValueError{Flag: name, Value: value.Value, Valid: []string{"true", "false"}}
+		case value.Value == "true":
+			return []string{alias}, nil
+		}
+		return nil, nil
+	}
+
+	var values []string
+	switch value.Kind {
+	case yaml.ScalarNode:
+		values = []string{value.Value}
+	case yaml.SequenceNode:
+		if f.Type != FlagStringList {
+			return nil, &InvalidValueError{Flag: display, Value: "[...]", Err: errors.New("takes a single value")}
+		}
+		for _, item := range value.Content {
+			if item.Kind != yaml.ScalarNode {
+				return nil, &InvalidValueError{Flag: display, Value: "[...]", Err: errors.New("expected a list of strings")}
+			}
+			values = append(values, item.Value)
+		}
+	default:
+		return nil, &InvalidValueError{Flag: display, Value: "{...}", Err: errors.New("expected a value")}
+	}
+
+	if f.Type == FlagBool {
+		switch {
+		case value.Tag != "!!bool":
+			return nil, &InvalidValueError{Flag: display, Value: value.Value, Valid: []string{"true", "false"}}
+		case value.Value == "true":
+			return []string{display}, nil
+		case negatable(f):
+			return []string{"--no-" + name}, nil
+		default:
+			return nil, nil
+		}
+	}
+	args := make([]string, len(values))
+	for i, v := range values {
+		args[i] = display + "=" + v
+	}
+	return args, nil
+}
+
+// parseDefault parses args on top of p one option at a time, skipping and
+// returning the ones that fail, operands and the options in commandLineOnly.
+func parseDefault(p *Parser, args []string) []error {
+	var problems []error
+	for i := 0; i < len(args); i++ {
+		consumed, err := p.parseArg(args, i)
+		i += consumed // a rejected value is skipped along with its option
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		if len(p.Args) > 0 {
+			problems = append(problems, fmt.Errorf("unexpected operand '%s'", p.Args[0]))
+			p.Args = p.Args[:0]
+		}
+		for _, name := range commandLineOnly {
+			if f := p.Lookup(name); f != nil && f.Changed {
+				problems = append(problems, fmt.Errorf("option '--%s' is only valid on the command line", name))
+				f.reset()
+			}
+		}
+	}
+	return problems
+}
+
+// splitOptions splits s into words like a POSIX shell would, honouring
+// single quotes, double quotes and backslash escapes, so that
+// LOGO_LS_OPTIONS="-I '*.o'" works as expected.
+func splitOptions(s string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range s {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped, inWord = true, true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,234 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// buildWith runs BuildConfigWithDefaults with a fixed config file and
+// $LOGO_LS_OPTIONS value.
+func buildWith(t *testing.T, config, env string, args ...string) (*Config, error) {
+	t.Helper()
+	load := func() (Defaults, error) {
+		return Defaults{ConfigPath: "config.yaml", Config: []byte(config), Env: env}, nil
+	}
+	cfg, _, err := BuildConfigWithDefaults(append([]string{"app"}, args...), load)
+	return cfg, err
+}
+
+// Verifies the precedence order: config file < $LOGO_LS_OPTIONS < command line.
+func TestDefaultsPrecedence(t *testing.T) {
+	tests := []struct {
+		config, env string
+		args        []string
+		expected    SortMode
+	}{
+		{"sort: time\n", "", nil, SortModTime},
+		{"sort: time\n", "--sort=size", nil, SortSize},
+		{"sort: time\n", "-S", []string{"-X"}, SortExtension},
+		{"sort: time\n", "", []string{"--sort=name"}, SortAlphabetical},
+		{"", "-t", []string{"-U"}, SortNone},
+	}
+	for _, tt := range tests {
+		cfg, err := buildWith(t, tt.config, tt.env, tt.args...)
+		if err != nil {
+			t.Fatalf("config %q, env %q, args %v: %v", tt.config, tt.env, tt.args, err)
+		}
+		if cfg.SortMode != tt.expected {
+			t.Errorf("config %q, env %q, args %v: expected SortMode %v, got %v", tt.config, tt.env, tt.args, tt.expected, cfg.SortMode)
+		}
+	}
+}
+
+// Verifies every kind of option can be set from the config file.
+func TestDefaultsConfigFile(t *testing.T) {
+	config := `
+format: long
+git-status: true
+color: never
+time-style: true
+width: 100
+ignore:
+  - "*.o"
+  - node_modules
+`
+	cfg, err := buildWith(t, config, "-I tmp", "-I", "*.a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.LongListingMode != LongListingDefault {
+		t.Errorf("expected LongListingDefault, got %v", cfg.LongListingMode)
+	}
+	if !cfg.GitStatus {
+		t.Error("expected GitStatus to be true")
+	}
+	if cfg.Color != ColorNever {
+		t.Errorf("expected ColorNever, got %v", cfg.Color)
+	}
+	if cfg.Width != 100 {
+		t.Errorf("expected Width 100, got %d", cfg.Width)
+	}
+	// repeatable options accumulate across sources
+	if want := []string{"*.o", "node_modules", "tmp", "*.a"}; !slices.Equal(cfg.IgnorePatterns, want) {
+		t.Errorf("expected IgnorePatterns %q, got %q", want, cfg.IgnorePatterns)
+	}
+	if len(cfg.FileList) != 1 || cfg.FileList[0] != "." {
+		t.Errorf("expected FileList [\".\"], got %v", cfg.FileList)
+	}
+}
+
+// Verifies the command line can undo a default.
+func TestDefaultsOverriddenByCommandLine(t *testing.T) {
+	cfg, err := buildWith(t, "recursive: true\nformat: long\n", "", "--no-recursive", "-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Recursive {
+		t.Error("expected --no-recursive to clear the configured recursive")
+	}
+	if cfg.LongListingMode != LongListingNone || !cfg.OneFilePerLine {
+		t.Errorf("expected -1 to replace the configured long format, got %v/%v", cfg.LongListingMode, cfg.OneFilePerLine)
+	}
+
+	cfg, err = buildWith(t, "", "--format=single-column", "-l")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.LongListingMode != LongListingDefault {
+		t.Errorf("expected -l to replace the default format, got %v", cfg.LongListingMode)
+	}
+}
+
+// Verifies --no-config skips loading the defaults altogether.
+func TestNoConfig(t *testing.T) {
+	load := func() (Defaults, error) {
+		t.Error("defaults loaded despite --no-config")
+		return Defaults{}, errors.New("unreachable")
+	}
+	cfg, _, err := BuildConfigWithDefaults([]string{"app", "--no-config"}, load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.SortMode != SortAlphabetical {
+		t.Errorf("expected the built-in SortAlphabetical, got %v", cfg.SortMode)
+	}
+}
+
+// Verifies bad defaults are reported with their source and line and
+// skipped, while the other entries still apply.
+func TestDefaultsErrors(t *testing.T) {
+	tests := []struct {
+		config, env string
+		msg         string
+	}{
+		{"sort: time\nbogus: 1\n", "", "config.yaml:2: unrecognized option '--bogus'"},
+		{"recur: true\n", "", "config.yaml:1: unrecognized option '--recur'"},
+		{"sort: sideways\n", "", "config.yaml:1: invalid argument 'sideways' for '--sort'\nValid arguments are: 'name', 'none', 'size', 'time', 'version', 'extension'"},
+		{"recursive: yes\n", "", "config.yaml:1: invalid argument 'yes' for '--recursive'\nValid arguments are: 'true', 'false'"},
+		{"long: yes\n", "", "config.yaml:1: invalid argument 'yes' for 'long'\nValid arguments are: 'true', 'false'"},
+		{"sort: [time, size]\n", "", "config.yaml:1: invalid argument '[...]' for '--sort': takes a single value"},
+		{"help: true\n", "", "config.yaml:1: option '--help' is only valid on the command line"},
+		{"- sort\n", "", "config.yaml:1: expected a mapping of option names to values"},
+		{"", "--bogus", "LOGO_LS_OPTIONS: unrecognized option '--bogus'"},
+		{"", "-l /tmp", "LOGO_LS_OPTIONS: unexpected operand '/tmp'"},
+		{"", "--no-conf", "LOGO_LS_OPTIONS: option '--no-config' is only valid on the command line"},
+		{"", "-I '*.o", "LOGO_LS_OPTIONS: unterminated quote or escape"},
+	}
+	for _, tt := range tests {
+		cfg, err := buildWith(t, tt.config, tt.env)
+		if err != nil {
+			t.Errorf("config %q, env %q: expected no error, got %v", tt.config, tt.env, err)
+			continue
+		}
+		if len(cfg.DefaultsProblems) != 1 {
+			t.Errorf("config %q, env %q: expected one problem, got %v", tt.config, tt.env, cfg.DefaultsProblems)
+			continue
+		}
+		var defaultsErr *DefaultsError
+		if got := cfg.DefaultsProblems[0]; !errors.As(got, &defaultsErr) || got.Error() != tt.msg {
+			t.Errorf("config %q, env %q: expected DefaultsError %q, got %q", tt.config, tt.env, tt.msg, got)
+		}
+	}
+}
+
+// Verifies a bad entry leaves the rest of the defaults, and the commands
+// that never read them, working.
+func TestDefaultsSkipBadEntries(t *testing.T) {
+	cfg, err := buildWith(t, "sort: sideways\nrecursive: true\nlong: true\n", "--bogus -I tmp --sort nope -D", "icons", "check")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.DefaultsProblems) != 3 {
+		t.Errorf("expected 3 problems, got %v", cfg.DefaultsProblems)
+	}
+	if !cfg.Recursive || !cfg.GitStatus || cfg.LongListingMode != LongListingDefault {
+		t.Errorf("expected the valid defaults to apply, got %+v", cfg)
+	}
+	if cfg.SortMode != SortAlphabetical {
+		t.Errorf("expected the built-in sort, got %v", cfg.SortMode)
+	}
+	if want := []string{"tmp"}; !slices.Equal(cfg.IgnorePatterns, want) {
+		t.Errorf("expected IgnorePatterns %q, got %q", want, cfg.IgnorePatterns)
+	}
+	if want := []string{"check"}; !slices.Equal(cfg.IconsCommand, want) {
+		t.Errorf("expected IconsCommand %q, got %q", want, cfg.IconsCommand)
+	}
+}
+
+// Verifies LOGO_LS_OPTIONS is split like shell words.
+func TestSplitOptions(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected []string
+	}{
+		{"", nil},
+		{"  -l\t-R  ", []string{"-l", "-R"}},
+		{"-I '*.o' -I \"build dir\"", []string{"-I", "*.o", "-I", "build dir"}},
+		{`-I my\ dir --sort=''time`, []string{"-I", "my dir", "--sort=time"}},
+		{`-I ''`, []string{"-I", ""}},
+	}
+	for _, tt := range tests {
+		got, err := splitOptions(tt.in)
+		if err != nil {
+			t.Errorf("splitOptions(%q): %v", tt.in, err)
+			continue
+		}
+		if !slices.Equal(got, tt.expected) {
+			t.Errorf("splitOptions(%q) = %q, expected %q", tt.in, got, tt.expected)
+		}
+	}
+}
+
+// Verifies LoadDefaults reads config.yaml from $XDG_CONFIG_HOME and the
+// environment variable, and tolerates a missing file.
+func TestLoadDefaults(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv(OptionsEnv, "-l")
+
+	d, err := LoadDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Config != nil || d.Env != "-l" {
+		t.Errorf("expected no config and env -l, got %q and %q", d.Config, d.Env)
+	}
+
+	path := filepath.Join(dir, "logo-ls", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("sort: size\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	d, err = LoadDefaults()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.ConfigPath != path || string(d.Config) != "sort: size\n" {
+		t.Errorf("expected %s with its contents, got %s: %q", path, d.ConfigPath, d.Config)
+	}
+}
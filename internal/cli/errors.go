@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnknownFlagError reports an option the parser doesn't know. Name includes
+// the leading dashes.
+type UnknownFlagError struct {
+	Name string
+}
+
+func (e *UnknownFlagError) Error() string {
+	if strings.HasPrefix(e.Name, "--") {
+		return fmt.Sprintf("unrecognized option '%s'", e.Name)
+	}
+	return fmt.Sprintf("invalid option -- '%s'", strings.TrimPrefix(e.Name, "-"))
+}
+
+// AmbiguousFlagError reports a long-option abbreviation that matches more
+// than one option.
+type AmbiguousFlagError struct {
+	Name       string
+	Candidates []string
+}
+
+func (e *AmbiguousFlagError) Error() string {
+	return fmt.Sprintf("option '%s' is ambiguous; possibilities: '%s'", e.Name, strings.Join(e.Candidates, "' '"))
+}
+
+// MissingValueError reports a value-taking option given without a value.
+type MissingValueError struct {
+	Flag string
+}
+
+func (e *MissingValueError) Error() string {
+	return fmt.Sprintf("option '%s' requires an argument", e.Flag)
+}
+
+// UnexpectedValueError reports a value attached to a boolean option.
+type UnexpectedValueError struct {
+	Flag string
+}
+
+func (e *UnexpectedValueError) Error() string {
+	return fmt.Sprintf("option '%s' doesn't allow an argument", e.Flag)
+}
+
+// InvalidValueError reports a value the option doesn't accept. Valid lists
+// the accepted values of enumerated options; Err carries the conversion or
+// range error otherwise.
+type InvalidValueError struct {
+	Flag  string
+	Value string
+	Valid []string
+	Err   error
+}
+
+func (e *InvalidValueError) Error() string {
+	msg := fmt.Sprintf("invalid argument '%s' for '%s'", e.Value, e.Flag)
+	if len(e.Valid) > 0 {
+		msg += "\nValid arguments are: '" + strings.Join(e.Valid, "', '") + "'"
+	} else if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *InvalidValueError) Unwrap() error { return e.Err }
+
+// DefaultsError reports a bad option in the config file or
+// $LOGO_LS_OPTIONS. Line is 0 when the source has no lines.
+type DefaultsError struct {
+	Source string
+	Line   int
+	Err    error
+}
+
+func (e *DefaultsError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %v", e.Source, e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.Source, e.Err)
+}
+
+func (e *DefaultsError) Unwrap() error { return e.Err }
+
+// UnknownCommandError reports a missing or unknown word after
+// "logo-ls icons". Name is empty when the word is missing.
+type UnknownCommandError struct {
+	Name  string
+	Valid []string
+}
+
+func (e *UnknownCommandError) Error() string {
+	msg := "missing icons command"
+	if e.Name != "" {
+		msg = fmt.Sprintf("unknown icons command '%s'", e.Name)
+	}
+	return msg + "\nValid commands are: '" + strings.Join(e.Valid, "', '") + "'"
+}
+
+// CommandUsageError reports an icons command given the wrong number of
+// arguments.
+type CommandUsageError struct {
+	Usage string
+}
+
+func (e *CommandUsageError) Error() string {
+	return "usage: logo-ls " + e.Usage
+}
@@ -3,7 +3,13 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"math"
 	"os"
+	"path"
+	"slices"
+	"strconv"
+
+	"github.com/canta2899/logo-ls/internal/icons"
 )
 
 // Version defines the app version.
@@ -12,13 +18,65 @@ import (
 // See the Makefile for details.
 var Version = ""
 
-// ErrHelp and ErrVersionRequested are returned by BuildConfig when the user
-// requested --help or --version.
+// ErrHelp, ErrVersionRequested, ErrManRequested and ErrReferenceRequested
+// are returned by BuildConfig when the user requested --help, --version,
+// --man or --markdown-reference.
 var (
-	ErrHelp             = errors.New("help requested")
-	ErrVersionRequested = errors.New("version requested")
+	ErrHelp               = errors.New("help requested")
+	ErrVersionRequested   = errors.New("version requested")
+	ErrManRequested       = errors.New("man page requested")
+	ErrReferenceRequested = errors.New("markdown reference requested")
 )
 
+// CompletionRequest is returned by BuildConfig when the user asked for a
+// shell completion script with --completion.
+type CompletionRequest struct {
+	Shell string
+}
+
+func (r *CompletionRequest) Error() string { return "completion requested for " + r.Shell }
+
+// sortWords maps the --sort values to the sort mode they select.
+var sortWords = map[string]SortMode{
+	"name":      SortAlphabetical,
+	"none":      SortNone,
+	"size":      SortSize,
+	"time":      SortModTime,
+	"version":   SortNatural,
+	"extension": SortExtension,
+}
+
+// SortModeFor returns the sort mode a --sort word selects.
+func SortModeFor(word string) (SortMode, bool) {
+	mode, ok := sortWords[word]
+	return mode, ok
+}
+
+// formatWords lists the --format values.
+var formatWords = []string{"vertical", "long", "single-column"}
+
+// colorWords maps the --color values to their ColorMode.
+var colorWords = map[string]ColorMode{
+	"always": ColorAlways,
+	"auto":   ColorAuto,
+	"never":  ColorNever,
+}
+
+// backgroundWords maps the --background values to their BackgroundMode.
+var backgroundWords = map[string]BackgroundMode{
+	"auto":  BackgroundAuto,
+	"dark":  BackgroundDark,
+	"light": BackgroundLight,
+}
+
+// iconSets maps the --icon-set values to their GlyphSet; "" is the default.
+var iconSets = map[string]icons.GlyphSet{
+	"":      icons.GlyphsNerd,
+	"nerd":  icons.GlyphsNerd,
+	"emoji": icons.GlyphsEmoji,
+	"ascii": icons.GlyphsASCII,
+}
+
 // BuildConfig parses the given arg list (including argv[0]) and returns a
 // Config and the parser used to build it. It never calls os.Exit, making it
 // safe to use from tests.
@@ -26,10 +84,33 @@ var (
 // On --help or --version, it returns the sentinel errors above so the caller
 // can choose how to render them. The parser is returned in all cases.
 func BuildConfig(argv []string) (*Config, *Parser, error) {
+	return BuildConfigWithDefaults(argv, nil)
+}
+
+// BuildConfigWithDefaults is BuildConfig with option defaults: unless the
+// command line has --no-config, the Defaults returned by load are applied
+// first, so options are resolved in this order, later ones winning:
+//
+//  1. built-in defaults
+//  2. the config file (see Defaults)
+//  3. $LOGO_LS_OPTIONS
+//  4. the command line
+//
+// load is only called when the defaults are needed; nil means none. Bad
+// defaults are not an error: they are skipped and listed in
+// Config.DefaultsProblems.
+func BuildConfigWithDefaults(argv []string, load func() (Defaults, error)) (*Config, *Parser, error) {
 	c := NewConfig()
 	opt := NewParser()
 	opt.Parameters = "[files ...]"
 
+	// "logo-ls icons ..." runs an icons command instead of listing files;
+	// ./icons lists a directory of that name.
+	iconsCommand := len(argv) > 1 && argv[1] == "icons"
+	if iconsCommand {
+		argv = append([]string{argv[0]}, argv[2:]...)
+	}
+
 	help := opt.Bool('?', "help", "display this help and exit")
 	version := opt.Bool('V', "version", "output version information and exit")
 
@@ -43,18 +124,27 @@ func BuildConfig(argv []string) (*Config, *Parser, error) {
 	sortExtension := opt.Bool('X', "", "sort alphabetically by entry extension")
 	sortModTime := opt.Bool('t', "", "sort by modification time, newest first")
 	sortSize := opt.Bool('S', "", "sort by file size, largest first")
+	sortWord := opt.Enum("sort", "", []string{"name", "none", "size", "time", "version", "extension"},
+		"sort by WORD instead of name: none (-U), size (-S), time (-t), version (-v), extension (-X)")
 
 	reverse := opt.Bool('r', "reverse", "reverse order while sorting")
 	recursive := opt.Bool('R', "recursive", "list subdirectories recursively")
+	oneFileSystem := opt.Bool('x', "one-file-system", "with -R, list mount points but do not descend into them")
 	gitStatus := opt.Bool('D', "git-status", "print git status of files")
 	disableIcon := opt.Bool('e', "disable-icon", "don't print icons of the files")
 	showInodeNumber := opt.Bool('i', "inode", "print the index number of each file")
 	oneFilePerLine := opt.Bool('1', "", "list one file per line.")
 	directory := opt.Bool('d', "directory", "list directories themselves, not their contents")
+	dereference := opt.Bool('L', "dereference", "show information for the file each symbolic link references rather than for the link itself")
+	dereferenceCommandLine := opt.Bool('H', "dereference-command-line", "follow symbolic links listed on the command line")
+	dereferenceCommandLineToDir := opt.Bool(0, "dereference-command-line-symlink-to-dir", "follow each command line symbolic link that points to a directory")
 	noGroup := opt.Bool('G', "no-group", "in a long listing, don't print group names")
 	humanReadable := opt.Bool('h', "human-readable", "with -l and -s, print sizes like 1K 234M 2G etc.")
 	showBlockSize := opt.Bool('s', "size", "print the allocated size of each file, in blocks")
 
+	width := opt.Int('w', "width", 0, "set output width to NUM; 0 means no limit")
+	ignore := opt.StringList('I', "ignore", "do not list implied entries matching shell PATTERN")
+
 	completeTimeInformation := opt.Bool('T', "time-style", "display complete time information")
 
 	c.LongListingMode = LongListingNone
@@ -62,9 +152,33 @@ func BuildConfig(argv []string) (*Config, *Parser, error) {
 	longListingMode := opt.Bool('o', "", "like -l, but do not list group information")
 	longListingGroup := opt.Bool('g', "", "like -l, but do not list owner")
 	longListingDefault := opt.Bool('l', "", "use a long listing format")
+	numericIDs := opt.Bool('n', "numeric-uid-gid", "like -l, but list numeric user and group IDs")
+	author := opt.Bool(0, "author", "with -l, print the author of each file")
+	octalPermissions := opt.Bool(0, "octal-permissions", "in a long listing, also show permissions in octal, e.g. 0755")
+	permissionColors := opt.Bool(0, "permission-colors", "in a long listing, color each letter of the permissions by kind, for the --background")
+	audit := opt.Bool(0, "audit", "flag risky files (world-writable, setuid, writable SSH keys, ...) and print a summary")
+	format := opt.Enum("format", "", formatWords, "layout: vertical (the default), long (-l), single-column (-1)")
+	color := opt.Enum("color", "", []string{"always", "auto", "never"}, "colorize the output: always (the default), auto (only on a terminal) or never")
 
 	noIconOverride := opt.Bool(0, "no-override", "disable loading the user icon override YAML")
-	iconOverrideFile := opt.String("override-file", "", "load icon overrides from this YAML file instead of the default paths")
+	iconOverrideFiles := opt.PathList("override-file", "also load icon overrides from this YAML file; repeatable, later files win")
+	checkOverrides := opt.Bool(0, "check-overrides", "report every problem in the icon override files and exit; exits 1 when there is one")
+	noConfig := opt.Bool(0, "no-config", "ignore the config file and $"+OptionsEnv)
+	noProject := opt.Bool(0, "no-project", "ignore .logo-ls.yaml project files")
+	debugIcons := opt.Bool(0, "debug-icons", "report on stderr which file supplied each icon")
+	sniff := opt.Bool(0, "sniff", "pick icons for unrecognised files from their first bytes")
+	background := opt.Enum("background", "", []string{"auto", "dark", "light"}, "pick icon colors for a dark or light terminal background; auto (the default) reads $COLORFGBG")
+	linkChain := opt.Bool(0, "link-chain", "in a long listing, show every hop of a symlink pointing to another symlink")
+	xattr := opt.Bool('@', "xattr", "in a long listing, list the name and size of each extended attribute under the file")
+	xattrValues := opt.Bool(0, "xattr-values", "like --xattr, and also print each attribute's value")
+	context := opt.Bool('Z', "context", "print the SELinux security context of each file")
+	fsType := opt.Bool(0, "fs-type", "print the type of the file system each file is on, as a column in a long listing")
+	attributes := opt.Bool(0, "attributes", "in a long listing, show the Linux file attributes set by chattr, as lsattr does, and mark files with capabilities")
+	iconSet := opt.Enum("icon-set", "", []string{"nerd", "emoji", "ascii"}, "draw icons from a Nerd Font (the default), emoji or plain-text tags like [go]")
+
+	completion := opt.Enum("completion", "", Shells, "print a completion script for the given shell and exit")
+	man := opt.Bool(0, "man", "print the roff man page and exit")
+	reference := opt.Bool(0, "markdown-reference", "print the command reference as markdown and exit")
 
 	if err := opt.Parse(argv); err != nil {
 		return nil, opt, err
@@ -76,6 +190,30 @@ func BuildConfig(argv []string) (*Config, *Parser, error) {
 	if *version {
 		return nil, opt, ErrVersionRequested
 	}
+	if *completion != "" {
+		return nil, opt, &CompletionRequest{Shell: *completion}
+	}
+	if *man {
+		return nil, opt, ErrManRequested
+	}
+	if *reference {
+		return nil, opt, ErrReferenceRequested
+	}
+
+	if load != nil && !*noConfig {
+		// An unreadable config file or a bad entry is reported and skipped;
+		// the rest of the defaults still apply.
+		defaults, err := load()
+		if err != nil {
+			c.DefaultsProblems = append(c.DefaultsProblems, err)
+		}
+		// Start over so the command line is parsed on top of the defaults.
+		opt.reset()
+		c.DefaultsProblems = append(c.DefaultsProblems, defaults.apply(opt)...)
+		if err := opt.Parse(argv); err != nil {
+			return nil, opt, err
+		}
+	}
 
 	switch {
 	case *includeAll:
@@ -84,17 +222,23 @@ func BuildConfig(argv []string) (*Config, *Parser, error) {
 		c.AllMode = IncludeAlmost
 	}
 
-	switch {
-	case *sortNone:
+	// The last sort option wins, so the command line can override a
+	// default sort from the config file.
+	sortOption := opt.Latest(sortNone, sortNatural, sortExtension, sortModTime, sortSize, sortWord)
+	c.SortChosen = sortOption != nil
+	switch sortOption {
+	case sortNone:
 		c.SortMode = SortNone
-	case *sortNatural:
+	case sortNatural:
 		c.SortMode = SortNatural
-	case *sortExtension:
+	case sortExtension:
 		c.SortMode = SortExtension
-	case *sortModTime:
+	case sortModTime:
 		c.SortMode = SortModTime
-	case *sortSize:
+	case sortSize:
 		c.SortMode = SortSize
+	case sortWord:
+		c.SortMode = sortWords[*sortWord]
 	}
 
 	switch {
@@ -102,24 +246,95 @@ func BuildConfig(argv []string) (*Config, *Parser, error) {
 		c.LongListingMode = LongListingOwner
 	case *longListingGroup:
 		c.LongListingMode = LongListingGroup
-	case *longListingDefault:
+	case *longListingDefault || *numericIDs:
 		c.LongListingMode = LongListingDefault
 	}
+	c.OneFilePerLine = *oneFilePerLine
+	c.NumericIDs = *numericIDs
+	c.Author = *author
+	c.OctalPermissions = *octalPermissions
+	c.PermissionColors = *permissionColors
+	c.Audit = *audit
+
+	// --format only applies when no later -l, -o, -g, -n or -1 replaced it.
+	if opt.Latest(format, longListingDefault, longListingMode, longListingGroup, numericIDs, oneFilePerLine) == format {
+		switch *format {
+		case "long":
+			c.LongListingMode, c.OneFilePerLine = LongListingDefault, false
+		case "single-column":
+			c.LongListingMode, c.OneFilePerLine = LongListingNone, true
+		case "vertical":
+			c.LongListingMode, c.OneFilePerLine = LongListingNone, false
+		}
+	}
+	if *color != "" {
+		c.Color = colorWords[*color]
+	}
+
+	switch {
+	case *dereference:
+		c.Dereference = DereferenceAll
+	case *dereferenceCommandLine:
+		c.Dereference = DereferenceCommandLine
+	case *dereferenceCommandLineToDir:
+		c.Dereference = DereferenceCommandLineDirs
+	case c.LongListingMode != LongListingNone || *directory:
+		c.Dereference = DereferenceNone
+	default:
+		c.Dereference = DereferenceCommandLineDirs
+	}
 
 	c.TimeFormatter = GetFormatter(*completeTimeInformation)
 
 	c.Reverse = *reverse
 	c.Recursive = *recursive
+	c.OneFileSystem = *oneFileSystem
 	c.GitStatus = *gitStatus
 	c.DisableIcon = *disableIcon
-	c.OneFilePerLine = *oneFilePerLine
 	c.Directory = *directory
 	c.NoGroup = *noGroup
 	c.HumanReadable = *humanReadable
 	c.ShowBlockSize = *showBlockSize
 	c.ShowInodeNumber = *showInodeNumber
 	c.NoIconOverride = *noIconOverride
-	c.IconOverrideFile = *iconOverrideFile
+	c.IconOverrideFiles = *iconOverrideFiles
+	c.CheckOverrides = *checkOverrides
+	c.IgnorePatterns = *ignore
+	c.NoProject = *noProject
+	c.DebugIcons = *debugIcons
+	c.Sniff = *sniff
+	c.IconSet = iconSets[*iconSet]
+	c.Background = backgroundWords[*background]
+	c.LinkChain = *linkChain
+	c.XAttr = *xattr || *xattrValues
+	c.XAttrValues = *xattrValues
+	c.Context = *context
+	c.Attributes = *attributes
+	c.FSType = *fsType
+
+	if opt.Changed("width") {
+		switch {
+		case *width < 0:
+			return nil, opt, &InvalidValueError{Flag: "--width", Value: strconv.Itoa(*width), Err: errors.New("must not be negative")}
+		case *width == 0:
+			c.Width = math.MaxInt32
+		default:
+			c.Width = *width
+		}
+	}
+	for _, pattern := range c.IgnorePatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, opt, &InvalidValueError{Flag: "--ignore", Value: pattern, Err: err}
+		}
+	}
+
+	if iconsCommand {
+		if err := checkIconsCommand(opt.Args); err != nil {
+			return nil, opt, err
+		}
+		c.IconsCommand = opt.Args
+		return c, opt, nil
+	}
 
 	if len(opt.Args) > 0 {
 		c.FileList = append(c.FileList, opt.Args...)
@@ -130,18 +345,54 @@ func BuildConfig(argv []string) (*Config, *Parser, error) {
 	return c, opt, nil
 }
 
-// GetConfigFromCli parses os.Args. On --help/--version it prints to stdout
-// and exits 0; on parse errors it prints to stderr and exits 2.
+// checkIconsCommand validates the words after "logo-ls icons": a known
+// command followed by its argument, if it takes one.
+func checkIconsCommand(words []string) error {
+	var valid []string
+	for _, c := range commandDocs {
+		valid = append(valid, c.Name)
+	}
+	if len(words) == 0 {
+		return &UnknownCommandError{Valid: valid}
+	}
+	i := slices.Index(valid, words[0])
+	if i < 0 {
+		return &UnknownCommandError{Name: words[0], Valid: valid}
+	}
+	want := 0
+	if commandDocs[i].Args != "" {
+		want = 1
+	}
+	if len(words)-1 != want {
+		return &CommandUsageError{Usage: commandUsage(commandDocs[i].Name, commandDocs[i].Args)}
+	}
+	return nil
+}
+
+// GetConfigFromCli parses os.Args. On --help, --version and the generators
+// (--completion, --man, --markdown-reference) it prints to stdout and exits 0; on parse errors it prints to stderr and exits 2.
 func GetConfigFromCli() *Config {
-	c, opt, err := BuildConfig(os.Args)
+	c, opt, err := BuildConfigWithDefaults(os.Args, LoadDefaults)
+	if err == nil {
+		for _, problem := range c.DefaultsProblems {
+			fmt.Fprintf(os.Stderr, "logo-ls: ignoring default option: %v\n", problem)
+		}
+	}
 	if err != nil {
+		var completion *CompletionRequest
 		switch {
+		case errors.As(err, &completion):
+			writeOrExit(WriteCompletion(os.Stdout, opt, completion.Shell))
 		case errors.Is(err, ErrHelp):
 			printHelpMessage(opt)
 			os.Exit(0)
 		case errors.Is(err, ErrVersionRequested):
 			printVersion()
 			os.Exit(0)
+		case errors.Is(err, ErrManRequested):
+			writeOrExit(WriteManPage(os.Stdout, opt))
+		case errors.Is(err, ErrReferenceRequested):
+			writeOrExit(WriteMarkdownReference(os.Stdout, opt))
 		default:
 			fmt.Fprintf(os.Stderr, "logo-ls: %v\nTry 'logo-ls --help' for more information.\n", err)
 			os.Exit(2)
@@ -150,16 +401,35 @@ func GetConfigFromCli() *Config {
 	return c
 }
 
+// writeOrExit terminates after a generator wrote to stdout: 0 on success, 2
+// with the error on stderr otherwise.
+func writeOrExit(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logo-ls: %v\n", err)
+		os.Exit(2)
+	}
+	os.Exit(0)
+}
+
 func printVersion() {
 	fmt.Printf("logo-ls %s\nLicense MIT <https://opensource.org/licenses/MIT>.\nThis is free software: you are free to change and redistribute it.\nThere is NO WARRANTY, to the extent permitted by law.\n", Version)
 }
 
+// summary and description are shared by --help and the generated man page.
+const (
+	summary     = "A minimal ls replacement, with git status indicators and configurable Nerd Font icons."
+	description = "Lists information about the FILEs (the current directory by default).\nSorts entries alphabetically if none of -tvSUX or --sort is specified."
+)
+
 func printHelpMessage(opt *Parser) {
-	fmt.Println("logo-ls: A minimal ls replacement, with git status indicators and configurable Nerd Font icons.")
-	fmt.Println("Lists information about the FILEs (the current directory by default).")
-	fmt.Println("Sorts entries alphabetically if none of -tvSUX is specified.")
+	fmt.Println("logo-ls: " + summary)
+	fmt.Println(description)
 	fmt.Println()
 	if opt != nil {
 		opt.PrintUsage()
 	}
+	fmt.Println("\nCommands:")
+	for _, c := range commandDocs {
+		fmt.Printf("  logo-ls %-26s %s\n", commandUsage(c.Name, c.Args), c.Description)
+	}
 }
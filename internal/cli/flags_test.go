@@ -1,9 +1,12 @@
 package cli
 
 import (
+	"errors"
+	"math"
 	"os"
+	"slices"
+	"strings"
 	"testing"
-
 )
 
 // reset os.Args before calling GetConfigFromCli.
@@ -160,20 +163,161 @@ func TestIconOverrideFlags(t *testing.T) {
 	if !cfg.NoIconOverride {
 		t.Error("expected NoIconOverride to be true")
 	}
-	if cfg.IconOverrideFile != "" {
-		t.Errorf("expected IconOverrideFile empty, got %q", cfg.IconOverrideFile)
+	if len(cfg.IconOverrideFiles) != 0 {
+		t.Errorf("expected IconOverrideFiles empty, got %q", cfg.IconOverrideFiles)
 	}
 
 	cfg = parseArgs([]string{"app", "--override-file", "/tmp/icons.yaml"})
 	if cfg.NoIconOverride {
 		t.Error("expected NoIconOverride to be false")
 	}
-	if cfg.IconOverrideFile != "/tmp/icons.yaml" {
-		t.Errorf("expected IconOverrideFile=/tmp/icons.yaml, got %q", cfg.IconOverrideFile)
+	if !slices.Equal(cfg.IconOverrideFiles, []string{"/tmp/icons.yaml"}) {
+		t.Errorf("expected IconOverrideFiles=[/tmp/icons.yaml], got %q", cfg.IconOverrideFiles)
+	}
+
+	cfg = parseArgs([]string{"app", "--override-file=/tmp/a.yaml", "--override-file", "/tmp/b.yaml"})
+	if !slices.Equal(cfg.IconOverrideFiles, []string{"/tmp/a.yaml", "/tmp/b.yaml"}) {
+		t.Errorf("expected both paths in order, got %q", cfg.IconOverrideFiles)
+	}
+}
+
+// Verifies "icons" as the first argument selects an icons command, with
+// the options still parsed.
+func TestIconsCommand(t *testing.T) {
+	cfg, _, err := BuildConfig([]string{"app", "icons", "check", "--no-override"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(cfg.IconsCommand, []string{"check"}) || !cfg.NoIconOverride {
+		t.Errorf("expected IconsCommand=[check] with --no-override, got %q %v", cfg.IconsCommand, cfg.NoIconOverride)
+	}
+
+	cfg, _, err = BuildConfig([]string{"app", "icons", "which", "main.go"})
+	if err != nil || !slices.Equal(cfg.IconsCommand, []string{"which", "main.go"}) {
+		t.Errorf("expected IconsCommand=[which main.go], got %q, %v", cfg.IconsCommand, err)
+	}
+
+	for _, argv := range [][]string{{"app", "icons"}, {"app", "icons", "nope"}} {
+		var cmdErr *UnknownCommandError
+		if _, _, err := BuildConfig(argv); !errors.As(err, &cmdErr) {
+			t.Errorf("%q: expected UnknownCommandError, got %v", argv, err)
+		}
+	}
+	for _, argv := range [][]string{{"app", "icons", "which"}, {"app", "icons", "list", "extra"}} {
+		var usageErr *CommandUsageError
+		if _, _, err := BuildConfig(argv); !errors.As(err, &usageErr) {
+			t.Errorf("%q: expected CommandUsageError, got %v", argv, err)
+		}
+	}
+
+	// anywhere else, icons is a file to list
+	for _, argv := range [][]string{{"app", "./icons"}, {"app", "-l", "icons"}} {
+		cfg, _, err := BuildConfig(argv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.IconsCommand != nil || len(cfg.FileList) != 1 {
+			t.Errorf("%q: expected a listing, got command %q files %q", argv, cfg.IconsCommand, cfg.FileList)
+		}
+	}
+}
+
+// Verifies GNU-style long options: unambiguous abbreviations, "--opt=value"
+// and "--no-" negation of boolean options.
+func TestLongOptionForms(t *testing.T) {
+	cfg := parseArgs([]string{"app", "--recur", "--hum", "--sort=time"})
+	if !cfg.Recursive || !cfg.HumanReadable {
+		t.Errorf("expected abbreviations to set Recursive and HumanReadable, got %+v", cfg)
+	}
+	if cfg.SortMode != SortModTime {
+		t.Errorf("expected SortMode SortModTime, got %v", cfg.SortMode)
+	}
+
+	cfg = parseArgs([]string{"app", "-R", "--no-recursive"})
+	if cfg.Recursive {
+		t.Error("expected --no-recursive to clear Recursive")
+	}
+
+	cfg = parseArgs([]string{"app", "--no-recursive", "-R"})
+	if !cfg.Recursive {
+		t.Error("expected the later -R to win over --no-recursive")
 	}
 
-	cfg = parseArgs([]string{"app", "--override-file=/tmp/inline.yaml"})
-	if cfg.IconOverrideFile != "/tmp/inline.yaml" {
-		t.Errorf("expected inline form to set path, got %q", cfg.IconOverrideFile)
+	// options whose name already starts with "no-" are matched as is
+	cfg = parseArgs([]string{"app", "-l", "--no-group"})
+	if !cfg.NoGroup {
+		t.Error("expected --no-group to set NoGroup")
+	}
+}
+
+// Verifies value-taking short options, attached or separate, and repeated
+// list options.
+func TestShortOptionValues(t *testing.T) {
+	tests := []struct {
+		args   []string
+		width  int
+		ignore []string
+	}{
+		{[]string{"app", "-w80"}, 80, nil},
+		{[]string{"app", "-w", "80"}, 80, nil},
+		{[]string{"app", "-1w", "80"}, 80, nil},
+		{[]string{"app", "--width=120"}, 120, nil},
+		{[]string{"app", "-w0"}, math.MaxInt32, nil},
+		{[]string{"app", "-I", "*.o"}, 0, []string{"*.o"}},
+		{[]string{"app", "-I*.o", "--ignore", "*.a", "--ign=tmp"}, 0, []string{"*.o", "*.a", "tmp"}},
+	}
+	for _, tt := range tests {
+		cfg := parseArgs(tt.args)
+		if cfg.Width != tt.width {
+			t.Errorf("for args %v, expected Width %d, got %d", tt.args, tt.width, cfg.Width)
+		}
+		if !slices.Equal(cfg.IgnorePatterns, tt.ignore) {
+			t.Errorf("for args %v, expected IgnorePatterns %q, got %q", tt.args, tt.ignore, cfg.IgnorePatterns)
+		}
+		if len(cfg.FileList) != 1 || cfg.FileList[0] != "." {
+			t.Errorf("for args %v, expected the value not to be an operand, got %v", tt.args, cfg.FileList)
+		}
+	}
+}
+
+// Verifies that BuildConfig reports bad options as typed errors instead of
+// exiting.
+func TestParseErrors(t *testing.T) {
+	var unknown *UnknownFlagError
+	var ambiguous *AmbiguousFlagError
+	var missing *MissingValueError
+	var unexpected *UnexpectedValueError
+	var invalid *InvalidValueError
+
+	tests := []struct {
+		args   []string
+		target any
+		msg    string
+	}{
+		{[]string{"app", "--bogus"}, &unknown, "unrecognized option '--bogus'"},
+		{[]string{"app", "-k"}, &unknown, "invalid option -- 'k'"},
+		{[]string{"app", "--re"}, &ambiguous, "option '--re' is ambiguous; possibilities: '--reverse' '--recursive'"},
+		{[]string{"app", "--no-re"}, &ambiguous, "option '--no-re' is ambiguous; possibilities: '--no-reverse' '--no-recursive'"},
+		{[]string{"app", "-w"}, &missing, "option '-w' requires an argument"},
+		{[]string{"app", "--override-file"}, &missing, "option '--override-file' requires an argument"},
+		{[]string{"app", "--all=yes"}, &unexpected, "option '--all' doesn't allow an argument"},
+		{[]string{"app", "--no-all=yes"}, &unexpected, "option '--no-all' doesn't allow an argument"},
+		{[]string{"app", "-wide"}, &invalid, "invalid argument 'ide' for '-w'"},
+		{[]string{"app", "--width=-1"}, &invalid, "invalid argument '-1' for '--width': must not be negative"},
+		{[]string{"app", "--sort=size2"}, &invalid, "invalid argument 'size2' for '--sort'"},
+		{[]string{"app", "-I", "[a"}, &invalid, "invalid argument '[a' for '--ignore': syntax error in pattern"},
+	}
+	for _, tt := range tests {
+		cfg, _, err := BuildConfig(tt.args)
+		if cfg != nil || err == nil {
+			t.Errorf("for args %v, expected an error, got config %+v", tt.args, cfg)
+			continue
+		}
+		if !errors.As(err, tt.target) {
+			t.Errorf("for args %v, expected %T, got %T (%v)", tt.args, tt.target, err, err)
+		}
+		if !strings.HasPrefix(err.Error(), tt.msg) {
+			t.Errorf("for args %v, expected message %q, got %q", tt.args, tt.msg, err.Error())
+		}
 	}
 }
@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/canta2899/logo-ls/internal/icons"
+)
+
+// envDocs lists the environment variables logo-ls reads.
+var envDocs = []struct {
+	Name        string
+	Description string
+}{
+	{"COLUMNS", "terminal width used for the multi-column layout when it cannot be queried from the terminal"},
+	{"LC_ALL, LC_COLLATE, LANG", "the first one set selects the collation; C and POSIX sort names bytewise, anything else case-insensitively"},
+	{"HOME", "folded to ~ in symlink targets and used to locate the icon override file"},
+	{"XDG_CONFIG_HOME", "base directory of the config and icon override files; defaults to ~/.config"},
+	{"COLORFGBG", "foreground and background palette indices set by some terminals; a background of 7 or 15 selects light icon colors with --background=auto"},
+	{OptionsEnv, "default options, split into words like a shell would; they override the config file and are overridden by the command line, and are ignored with --no-config"},
+}
+
+// commandDocs describes the icons commands for --help and the man page.
+// Args names the command's single argument; commands without one take no
+// arguments.
+var commandDocs = []struct {
+	Name        string
+	Args        string
+	Description string
+}{
+	{"check", "", "check the built-in and override glyphs against the Nerd Fonts v3 codepoints, suggesting the v3 replacement of removed ones; exits 1 when a glyph is missing"},
+	{"list", "", "list every icon mapping with its glyph, color and source: path rules, file names, patterns, sub-extensions, extensions and directories"},
+	{"which", "FILE", "explain which built-in table or rule, and which override entry, picked the icon of FILE"},
+	{"search", "TERM", "list the mappings whose key or icon name contains TERM"},
+}
+
+// commandUsage returns the synopsis of the icons command c.
+func commandUsage(name, args string) string {
+	return strings.TrimSpace("icons " + name + " " + args)
+}
+
+// projectFileDoc describes .logo-ls.yaml for the FILES section.
+const projectFileDoc = "per-project icons (in the sections of the override file), hide patterns and default sort, read from each listed directory and its parents up to the git root or $HOME, and only inside one of them; files owned by another user are skipped; nearer files win and all of them are layered over the icon overrides; ignored with --no-project"
+
+// configFileDoc describes the config file for the FILES section.
+const configFileDoc = "default options as a YAML mapping of long option names to values, e.g. \"sort: time\" or \"format: long\" (also \"long: true\"); bad entries are reported and skipped; ignored with --no-config"
+
+// metavar is the placeholder shown for an option's value.
+func metavar(f *Flag) string {
+	switch {
+	case f.Hint == HintFile:
+		return "FILE"
+	case len(f.Values) > 0:
+		return "WORD"
+	case f.Type == FlagInt:
+		return "NUM"
+	case f.Type == FlagStringList:
+		// the only repeatable options are name filters (-I)
+		return "PATTERN"
+	default:
+		return "VALUE"
+	}
+}
+
+// valueSeparator joins an option name to its metavar: "--opt=VALUE" for
+// long options, "-o VALUE" for short ones.
+func valueSeparator(name string) string {
+	if strings.HasPrefix(name, "--") {
+		return "="
+	}
+	return " "
+}
+
+// roffEscape escapes s for use in roff running text.
+func roffEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// WriteManPage writes a roff man page for logo-ls(1) to w, built from
+// p.Flags, the icon override locations and the exit codes.
+func WriteManPage(w io.Writer, p *Parser) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, ".TH LOGO\\-LS 1 \"\" \"logo-ls %s\" \"User Commands\"\n", roffEscape(Version))
+	fmt.Fprintln(bw, ".SH NAME")
+	fmt.Fprintf(bw, "logo\\-ls \\- %s\n", roffEscape(summary))
+	fmt.Fprintln(bw, ".SH SYNOPSIS")
+	fmt.Fprintf(bw, ".B logo\\-ls\n[\\fIOPTION\\fR]... [\\fIFILE\\fR]...\n")
+	fmt.Fprintln(bw, ".br")
+	fmt.Fprintf(bw, ".B logo\\-ls icons\n\\fICOMMAND\\fR [\\fIOPTION\\fR]...\n")
+	fmt.Fprintln(bw, ".SH DESCRIPTION")
+	for line := range strings.SplitSeq(description, "\n") {
+		fmt.Fprintln(bw, roffEscape(line))
+		fmt.Fprintln(bw, ".br")
+	}
+	fmt.Fprintln(bw, ".SH OPTIONS")
+	for _, f := range p.Flags {
+		fmt.Fprintln(bw, ".TP")
+		var names []string
+		for _, name := range optionNames(f) {
+			n := `\fB` + roffEscape(name) + `\fR`
+			if takesValue(f) {
+				n += valueSeparator(name) + `\fI` + metavar(f) + `\fR`
+			}
+			names = append(names, n)
+		}
+		fmt.Fprintln(bw, strings.Join(names, ", "))
+		fmt.Fprintln(bw, roffEscape(f.Description))
+		if len(f.Values) > 0 {
+			fmt.Fprintln(bw, ".br")
+			fmt.Fprintf(bw, "\\fIWORD\\fR is one of: %s.\n", roffEscape(strings.Join(f.Values, ", ")))
+		}
+	}
+	fmt.Fprintln(bw, ".SH COMMANDS")
+	fmt.Fprintln(bw, roffEscape("The icons commands inspect the icon tables instead of listing files; use ./icons to list a directory named icons."))
+	for _, c := range commandDocs {
+		fmt.Fprintln(bw, ".TP")
+		fmt.Fprintf(bw, ".B logo\\-ls %s\n", roffEscape(commandUsage(c.Name, c.Args)))
+		fmt.Fprintln(bw, roffEscape(c.Description))
+	}
+	fmt.Fprintln(bw, ".SH ENVIRONMENT")
+	for _, e := range envDocs {
+		fmt.Fprintln(bw, ".TP")
+		fmt.Fprintf(bw, ".B %s\n", roffEscape(e.Name))
+		fmt.Fprintln(bw, roffEscape(e.Description))
+	}
+	fmt.Fprintln(bw, ".SH FILES")
+	fmt.Fprintln(bw, ".TP")
+	fmt.Fprintf(bw, ".I %s\n", roffEscape(ConfigPathDoc))
+	fmt.Fprintln(bw, roffEscape(configFileDoc))
+	fmt.Fprintln(bw, ".TP")
+	fmt.Fprintln(bw, ".I .logo\\-ls.yaml")
+	fmt.Fprintln(bw, roffEscape(projectFileDoc))
+	fmt.Fprintln(bw, ".PP")
+	fmt.Fprintln(bw, "Icon overrides are read from every one of these files that exists, then from")
+	fmt.Fprintln(bw, `each \fB\-\-override\-file\fR; later files win entry by entry. \fB\-\-no\-override\fR skips them all:`)
+	for _, path := range icons.CandidatePathDocs() {
+		fmt.Fprintln(bw, ".TP")
+		fmt.Fprintf(bw, ".I %s\n", roffEscape(path))
+	}
+	fmt.Fprintln(bw, ".SH \"EXIT STATUS\"")
+	for _, c := range ExitCodeDocs {
+		fmt.Fprintln(bw, ".TP")
+		fmt.Fprintf(bw, ".B %d\n", c.Code)
+		fmt.Fprintln(bw, roffEscape(c.Description))
+	}
+	fmt.Fprintln(bw, ".SH \"SEE ALSO\"")
+	fmt.Fprintln(bw, ".BR ls (1)")
+	return bw.Flush()
+}
+
+// WriteMarkdownReference writes the same content as WriteManPage as a
+// markdown document, for the project documentation.
+func WriteMarkdownReference(w io.Writer, p *Parser) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "# logo-ls(1)")
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "<!-- generated by `logo-ls --markdown-reference`; do not edit. -->")
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "## Name")
+	fmt.Fprintln(bw)
+	fmt.Fprintf(bw, "logo-ls - %s\n", summary)
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "## Synopsis")
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "```")
+	fmt.Fprintln(bw, "logo-ls [OPTION]... [FILE]...")
+	fmt.Fprintln(bw, "logo-ls icons COMMAND [OPTION]...")
+	fmt.Fprintln(bw, "```")
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "## Description")
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, strings.ReplaceAll(description, "\n", "\n\n"))
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "## Options")
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "| Option | Description |")
+	fmt.Fprintln(bw, "| ------ | ----------- |")
+	for _, f := range p.Flags {
+		var names []string
+		for _, name := range optionNames(f) {
+			if takesValue(f) {
+				name += valueSeparator(name) + metavar(f)
+			}
+			names = append(names, "`"+name+"`")
+		}
+		desc := f.Description
+		if len(f.Values) > 0 {
+			desc += "; `WORD` is one of " + "`" + strings.Join(f.Values, "`, `") + "`"
+		}
+		fmt.Fprintf(bw, "| %s | %s |\n", strings.Join(names, ", "), strings.ReplaceAll(desc, "|", `\|`))
+	}
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "## Commands")
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "The icons commands inspect the icon tables instead of listing files; use `./icons` to list a directory named icons.")
+	fmt.Fprintln(bw)
+	for _, c := range commandDocs {
+		fmt.Fprintf(bw, "- `logo-ls %s`: %s\n", commandUsage(c.Name, c.Args), c.Description)
+	}
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "## Environment")
+	fmt.Fprintln(bw)
+	for _, e := range envDocs {
+		fmt.Fprintf(bw, "- `%s`: %s\n", e.Name, e.Description)
+	}
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "## Files")
+	fmt.Fprintln(bw)
+	fmt.Fprintf(bw, "- `%s`: %s\n", ConfigPathDoc, configFileDoc)
+	fmt.Fprintf(bw, "- `.logo-ls.yaml`: %s\n", projectFileDoc)
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "Icon overrides are read from every one of these files that exists, then from each `--override-file`; later files win entry by entry. `--no-override` skips them all:")
+	fmt.Fprintln(bw)
+	for _, path := range icons.CandidatePathDocs() {
+		fmt.Fprintf(bw, "- `%s`\n", path)
+	}
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "## Exit status")
+	fmt.Fprintln(bw)
+	for _, c := range ExitCodeDocs {
+		fmt.Fprintf(bw, "- `%d`: %s\n", c.Code, c.Description)
+	}
+	return bw.Flush()
+}
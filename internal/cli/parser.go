@@ -2,6 +2,8 @@ package cli
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -10,6 +12,17 @@ type FlagType int
 const (
 	FlagBool FlagType = iota
 	FlagString
+	FlagInt
+	FlagEnum
+	FlagStringList
+)
+
+// ValueHint tells shell completions what kind of value an option takes.
+type ValueHint int
+
+const (
+	HintNone ValueHint = iota
+	HintFile
 )
 
 type Flag struct {
@@ -18,6 +31,15 @@ type Flag struct {
 	Description string
 	Type        FlagType
 	Value       any
+	// Values lists the accepted values of an enumerated option. When set,
+	// the parser rejects anything else.
+	Values []string
+	Hint   ValueHint
+	// Changed is set once the flag appears on the command line.
+	Changed bool
+
+	def   any // default value, restored by reset
+	order int // when the flag was last set, for Latest
 }
 
 type Parser struct {
@@ -25,6 +47,8 @@ type Parser struct {
 	Args       []string
 	Usage      string
 	Parameters string
+
+	seq int // number of flags set so far
 }
 
 func NewParser() *Parser {
@@ -34,63 +58,210 @@ func NewParser() *Parser {
 	}
 }
 
+func (p *Parser) add(f *Flag) *Flag {
+	switch v := f.Value.(type) {
+	case *bool:
+		f.def = *v
+	case *string:
+		f.def = *v
+	case *int:
+		f.def = *v
+	}
+	p.Flags = append(p.Flags, f)
+	return f
+}
+
+// mark records that f was just set.
+func (p *Parser) mark(f *Flag) {
+	p.seq++
+	f.Changed = true
+	f.order = p.seq
+}
+
+// reset restores every flag to its default and drops the parsed operands,
+// so the same parser can parse again from scratch.
+func (p *Parser) reset() {
+	for _, f := range p.Flags {
+		f.reset()
+	}
+	p.Args = []string{}
+}
+
+// reset restores f to its default value, as if it was never set.
+func (f *Flag) reset() {
+	switch v := f.Value.(type) {
+	case *bool:
+		*v = f.def.(bool)
+	case *string:
+		*v = f.def.(string)
+	case *int:
+		*v = f.def.(int)
+	case *[]string:
+		*v = []string{}
+	}
+	f.Changed = false
+	f.order = 0
+}
+
 func (p *Parser) String(long, defaultValue, description string) *string {
 	val := defaultValue
-	f := &Flag{
+	p.add(&Flag{
 		Long:        long,
 		Description: description,
 		Type:        FlagString,
 		Value:       &val,
-	}
-	p.Flags = append(p.Flags, f)
+	})
+	return &val
+}
+
+// Path is like String but marks the value as a filesystem path so shell
+// completions offer file names.
+func (p *Parser) Path(long, defaultValue, description string) *string {
+	val := p.String(long, defaultValue, description)
+	p.Flags[len(p.Flags)-1].Hint = HintFile
+	return val
+}
+
+// Enum is like String but only accepts one of values.
+func (p *Parser) Enum(long, defaultValue string, values []string, description string) *string {
+	val := defaultValue
+	p.add(&Flag{
+		Long:        long,
+		Description: description,
+		Type:        FlagEnum,
+		Value:       &val,
+		Values:      values,
+	})
+	return &val
+}
+
+// Int registers an integer option. Short may be 0 for long-only options.
+func (p *Parser) Int(short rune, long string, defaultValue int, description string) *int {
+	val := defaultValue
+	p.add(&Flag{
+		Short:       short,
+		Long:        long,
+		Description: description,
+		Type:        FlagInt,
+		Value:       &val,
+	})
+	return &val
+}
+
+// PathList is a StringList with no short form whose values complete as
+// file names.
+func (p *Parser) PathList(long, description string) *[]string {
+	val := p.StringList(0, long, description)
+	p.Flags[len(p.Flags)-1].Hint = HintFile
+	return val
+}
+
+// StringList registers a repeatable option; every occurrence appends its
+// value. Short may be 0 for long-only options.
+func (p *Parser) StringList(short rune, long string, description string) *[]string {
+	val := []string{}
+	p.add(&Flag{
+		Short:       short,
+		Long:        long,
+		Description: description,
+		Type:        FlagStringList,
+		Value:       &val,
+	})
 	return &val
 }
 
 func (p *Parser) Bool(short rune, long string, description string) *bool {
 	val := false
-	f := &Flag{
+	p.add(&Flag{
 		Short:       short,
 		Long:        long,
 		Description: description,
 		Type:        FlagBool,
 		Value:       &val,
-	}
-	p.Flags = append(p.Flags, f)
+	})
 	return &val
 }
 
+// Lookup returns the flag with exactly the given long name, or nil.
+func (p *Parser) Lookup(long string) *Flag {
+	for _, f := range p.Flags {
+		if f.Long != "" && f.Long == long {
+			return f
+		}
+	}
+	return nil
+}
+
+// Changed reports whether the flag with the given long name was set on the
+// command line.
+func (p *Parser) Changed(long string) bool {
+	f := p.Lookup(long)
+	return f != nil && f.Changed
+}
+
+// Latest returns whichever of values (pointers returned when registering
+// flags) was set last, or nil when none was set. It lets mutually
+// exclusive options such as -t and --sort follow GNU's "last one wins".
+func (p *Parser) Latest(values ...any) any {
+	var latest *Flag
+	for _, f := range p.Flags {
+		if !f.Changed || !slices.Contains(values, f.Value) {
+			continue
+		}
+		if latest == nil || f.order > latest.order {
+			latest = f
+		}
+	}
+	if latest == nil {
+		return nil
+	}
+	return latest.Value
+}
+
+// Parse consumes args (including argv[0]) GNU style: long options may be
+// abbreviated to any unambiguous prefix and take values as "--opt=value" or
+// "--opt value"; boolean long options can be negated with "--no-opt"; short
+// options can be clustered and take values attached ("-w80") or as the next
+// argument ("-w 80"). Everything after "--" is positional.
+//
+// Errors are one of the typed errors in errors.go.
 func (p *Parser) Parse(args []string) error {
 	// Skip executable name
 	if len(args) > 0 {
 		args = args[1:]
 	}
+	return p.parse(args)
+}
 
+// parse is Parse without the leading executable name.
+func (p *Parser) parse(args []string) error {
 	for i := 0; i < len(args); i++ {
-		arg := args[i]
-
-		if arg == "--" {
-			p.Args = append(p.Args, args[i+1:]...)
-			break
-		}
-
-		switch {
-		case strings.HasPrefix(arg, "--"):
-			consumed, err := p.parseLong(args, i)
-			if err != nil {
-				return err
-			}
-			i += consumed
-		case strings.HasPrefix(arg, "-") && len(arg) > 1:
-			if err := p.parseShortCluster(arg[1:]); err != nil {
-				return err
-			}
-		default:
-			p.Args = append(p.Args, arg)
+		consumed, err := p.parseArg(args, i)
+		if err != nil {
+			return err
 		}
+		i += consumed
 	}
 	return nil
 }
 
+// parseArg parses args[i], an option, a cluster of short options or an
+// operand. Returns the number of additional args consumed.
+func (p *Parser) parseArg(args []string, i int) (int, error) {
+	arg := args[i]
+	switch {
+	case arg == "--":
+		p.Args = append(p.Args, args[i+1:]...)
+		return len(args) - i - 1, nil
+	case strings.HasPrefix(arg, "--"):
+		return p.parseLong(args, i)
+	case strings.HasPrefix(arg, "-") && len(arg) > 1:
+		return p.parseShortCluster(args, i)
+	}
+	p.Args = append(p.Args, arg)
+	return 0, nil
+}
+
 // parseLong handles a "--name[=value]" token. Returns the number of additional
 // args consumed (0 or 1 for a value following the flag).
 func (p *Parser) parseLong(args []string, i int) (int, error) {
@@ -103,61 +274,154 @@ func (p *Parser) parseLong(args []string, i int) (int, error) {
 		hasInline = true
 	}
 
-	f := p.findLong(name)
-	if f == nil {
-		return 0, fmt.Errorf("unknown flag: --%s", name)
+	f, negated, err := p.findLong(name)
+	if err != nil {
+		return 0, err
+	}
+	if negated {
+		if hasInline {
+			return 0, &UnexpectedValueError{Flag: "--no-" + f.Long}
+		}
+		*(f.Value.(*bool)) = false
+		p.mark(f)
+		return 0, nil
+	}
+	return p.assignLong(f, args, i, inlineValue, hasInline)
+}
+
+// longMatch is one spelling a long option can be abbreviated from.
+type longMatch struct {
+	flag    *Flag
+	negated bool
+}
+
+func (m longMatch) name() string {
+	if m.negated {
+		return "--no-" + m.flag.Long
 	}
-	return p.assignLong(f, name, args, i, inlineValue, hasInline)
+	return "--" + m.flag.Long
 }
 
-func (p *Parser) findLong(name string) *Flag {
+// negatable reports whether f accepts the "--no-" prefix. Options whose
+// name already starts with "no-" are excluded so "--no-no-group" never
+// shows up as a possibility.
+func negatable(f *Flag) bool {
+	return f.Type == FlagBool && f.Long != "" && !strings.HasPrefix(f.Long, "no-")
+}
+
+// findLong resolves name to a flag: an exact name wins, then an exact
+// "no-" negation, then a unique prefix of either.
+func (p *Parser) findLong(name string) (*Flag, bool, error) {
+	var matches []longMatch
 	for _, f := range p.Flags {
+		if f.Long == "" {
+			continue
+		}
 		if f.Long == name {
-			return f
+			return f, false, nil
+		}
+		if negatable(f) && "no-"+f.Long == name {
+			return f, true, nil
+		}
+		if strings.HasPrefix(f.Long, name) {
+			matches = append(matches, longMatch{flag: f})
+		}
+		if negatable(f) && strings.HasPrefix("no-"+f.Long, name) {
+			matches = append(matches, longMatch{flag: f, negated: true})
 		}
 	}
-	return nil
+	switch len(matches) {
+	case 0:
+		return nil, false, &UnknownFlagError{Name: "--" + name}
+	case 1:
+		return matches[0].flag, matches[0].negated, nil
+	}
+	candidates := make([]string, len(matches))
+	for i, m := range matches {
+		candidates[i] = m.name()
+	}
+	return nil, false, &AmbiguousFlagError{Name: "--" + name, Candidates: candidates}
 }
 
-func (p *Parser) assignLong(f *Flag, name string, args []string, i int, inlineValue string, hasInline bool) (int, error) {
-	switch f.Type {
-	case FlagBool:
+func (p *Parser) assignLong(f *Flag, args []string, i int, inlineValue string, hasInline bool) (int, error) {
+	display := "--" + f.Long
+	if f.Type == FlagBool {
 		if hasInline {
-			return 0, fmt.Errorf("flag --%s does not take a value", name)
+			return 0, &UnexpectedValueError{Flag: display}
 		}
 		*(f.Value.(*bool)) = true
+		p.mark(f)
 		return 0, nil
-	case FlagString:
-		if hasInline {
-			*(f.Value.(*string)) = inlineValue
-			return 0, nil
-		}
+	}
+	value, consumed := inlineValue, 0
+	if !hasInline {
 		if i+1 >= len(args) {
-			return 0, fmt.Errorf("flag --%s requires a value", name)
+			return 0, &MissingValueError{Flag: display}
 		}
-		*(f.Value.(*string)) = args[i+1]
-		return 1, nil
+		value, consumed = args[i+1], 1
 	}
-	return 0, nil
+	return consumed, p.setValue(f, display, value)
 }
 
-func (p *Parser) parseShortCluster(chars string) error {
-	for _, char := range chars {
-		if !p.setShortBool(char) {
-			return fmt.Errorf("unknown flag: -%c", char)
+// setValue stores value into a value-taking flag, validating it for its type.
+func (p *Parser) setValue(f *Flag, display, value string) error {
+	switch f.Type {
+	case FlagString:
+		*(f.Value.(*string)) = value
+	case FlagEnum:
+		if !slices.Contains(f.Values, value) {
+			return &InvalidValueError{Flag: display, Value: value, Valid: f.Values}
 		}
+		*(f.Value.(*string)) = value
+	case FlagInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return &InvalidValueError{Flag: display, Value: value, Err: err}
+		}
+		*(f.Value.(*int)) = n
+	case FlagStringList:
+		list := f.Value.(*[]string)
+		*list = append(*list, value)
 	}
+	p.mark(f)
 	return nil
 }
 
-func (p *Parser) setShortBool(char rune) bool {
-	for _, f := range p.Flags {
-		if f.Short == char && f.Type == FlagBool {
+// parseShortCluster handles a "-abc" token. A value-taking option ends the
+// cluster: the rest of the token is its value, or the next arg when the
+// option is the last character. Returns the number of additional args
+// consumed.
+func (p *Parser) parseShortCluster(args []string, i int) (int, error) {
+	chars := []rune(args[i][1:])
+	for j, char := range chars {
+		f := p.findShort(char)
+		if f == nil {
+			return 0, &UnknownFlagError{Name: "-" + string(char)}
+		}
+		if f.Type == FlagBool {
 			*(f.Value.(*bool)) = true
-			return true
+			p.mark(f)
+			continue
+		}
+		display := "-" + string(char)
+		if rest := string(chars[j+1:]); rest != "" {
+			return 0, p.setValue(f, display, rest)
+		}
+		if i+1 >= len(args) {
+			return 0, &MissingValueError{Flag: display}
+		}
+		return 1, p.setValue(f, display, args[i+1])
+	}
+	return 0, nil
+}
+
+func (p *Parser) findShort(char rune) *Flag {
+	for _, f := range p.Flags {
+		if f.Short == char {
+			return f
 		}
 	}
-	return false
+	return nil
 }
 
 func (p *Parser) PrintUsage() {
@@ -171,10 +435,12 @@ func (p *Parser) PrintUsage() {
 		longStr := ""
 		if f.Long != "" {
 			longStr = fmt.Sprintf("--%s", f.Long)
-			if f.Type == FlagString {
-				longStr += " <value>"
+			if f.Type != FlagBool {
+				longStr += " <" + strings.ToLower(metavar(f)) + ">"
 			}
 		}
 		fmt.Printf("  %-4s %-28s %s\n", shortStr, longStr, f.Description)
 	}
+	fmt.Println("\nLong options may be abbreviated to any unambiguous prefix; boolean")
+	fmt.Println("long options can be turned off again with --no-<option>.")
 }
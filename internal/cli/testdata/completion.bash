@@ -0,0 +1,64 @@
+# bash completion for logo-ls
+# generated by `logo-ls --completion bash`; do not edit.
+
+_logo_ls() {
+    local cur prev
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+    # "--opt=value" is split into "--opt" "=" "value" by COMP_WORDBREAKS.
+    if [[ "$cur" == "=" ]]; then
+        prev="${COMP_WORDS[COMP_CWORD-1]}"
+        cur=""
+    elif [[ "$prev" == "=" ]]; then
+        prev="${COMP_WORDS[COMP_CWORD-2]}"
+    fi
+
+    case "$prev" in
+        --sort)
+            COMPREPLY=($(compgen -W "name none size time version extension" -- "$cur"))
+            return
+            ;;
+        -w|--width)
+            COMPREPLY=()
+            return
+            ;;
+        -I|--ignore)
+            COMPREPLY=()
+            return
+            ;;
+        --format)
+            COMPREPLY=($(compgen -W "vertical long single-column" -- "$cur"))
+            return
+            ;;
+        --color)
+            COMPREPLY=($(compgen -W "always auto never" -- "$cur"))
+            return
+            ;;
+        --override-file)
+            COMPREPLY=($(compgen -f -- "$cur"))
+            return
+            ;;
+        --background)
+            COMPREPLY=($(compgen -W "auto dark light" -- "$cur"))
+            return
+            ;;
+        --icon-set)
+            COMPREPLY=($(compgen -W "nerd emoji ascii" -- "$cur"))
+            return
+            ;;
+        --completion)
+            COMPREPLY=($(compgen -W "bash zsh fish powershell" -- "$cur"))
+            return
+            ;;
+    esac
+
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=($(compgen -W "-? --help -V --version -a --all -A --almost-all -U -v -X -t -S --sort -r --reverse -R --recursive -x --one-file-system -D --git-status -e --disable-icon -i --inode -1 -d --directory -L --dereference -H --dereference-command-line --dereference-command-line-symlink-to-dir -G --no-group -h --human-readable -s --size -w --width -I --ignore -T --time-style -o -g -l -n --numeric-uid-gid --author --octal-permissions --permission-colors --audit --format --color --no-override --override-file --check-overrides --no-config --no-project --debug-icons --sniff --background --link-chain -@ --xattr --xattr-values -Z --context --fs-type --attributes --icon-set --completion --man --markdown-reference" -- "$cur"))
+        return
+    fi
+
+    COMPREPLY=($(compgen -f -- "$cur"))
+}
+
+complete -o filenames -F _logo_ls logo-ls
@@ -0,0 +1,58 @@
+# fish completion for logo-ls
+# generated by `logo-ls --completion fish`; do not edit.
+
+complete -c logo-ls -s '?' -l help -d 'display this help and exit'
+complete -c logo-ls -s 'V' -l version -d 'output version information and exit'
+complete -c logo-ls -s 'a' -l all -d 'do not ignore entries starting with .'
+complete -c logo-ls -s 'A' -l almost-all -d 'do not list implied . and ..'
+complete -c logo-ls -s 'U' -d 'do not sort; list entries in directory order'
+complete -c logo-ls -s 'v' -d 'natural sort of (version) numbers within text'
+complete -c logo-ls -s 'X' -d 'sort alphabetically by entry extension'
+complete -c logo-ls -s 't' -d 'sort by modification time, newest first'
+complete -c logo-ls -s 'S' -d 'sort by file size, largest first'
+complete -c logo-ls -l sort -x -a 'name none size time version extension' -d 'sort by WORD instead of name: none (-U), size (-S), time (-t), version (-v), extension (-X)'
+complete -c logo-ls -s 'r' -l reverse -d 'reverse order while sorting'
+complete -c logo-ls -s 'R' -l recursive -d 'list subdirectories recursively'
+complete -c logo-ls -s 'x' -l one-file-system -d 'with -R, list mount points but do not descend into them'
+complete -c logo-ls -s 'D' -l git-status -d 'print git status of files'
+complete -c logo-ls -s 'e' -l disable-icon -d 'don\'t print icons of the files'
+complete -c logo-ls -s 'i' -l inode -d 'print the index number of each file'
+complete -c logo-ls -s '1' -d 'list one file per line.'
+complete -c logo-ls -s 'd' -l directory -d 'list directories themselves, not their contents'
+complete -c logo-ls -s 'L' -l dereference -d 'show information for the file each symbolic link references rather than for the link itself'
+complete -c logo-ls -s 'H' -l dereference-command-line -d 'follow symbolic links listed on the command line'
+complete -c logo-ls -l dereference-command-line-symlink-to-dir -d 'follow each command line symbolic link that points to a directory'
+complete -c logo-ls -s 'G' -l no-group -d 'in a long listing, don\'t print group names'
+complete -c logo-ls -s 'h' -l human-readable -d 'with -l and -s, print sizes like 1K 234M 2G etc.'
+complete -c logo-ls -s 's' -l size -d 'print the allocated size of each file, in blocks'
+complete -c logo-ls -s 'w' -l width -x -d 'set output width to NUM; 0 means no limit'
+complete -c logo-ls -s 'I' -l ignore -x -d 'do not list implied entries matching shell PATTERN'
+complete -c logo-ls -s 'T' -l time-style -d 'display complete time information'
+complete -c logo-ls -s 'o' -d 'like -l, but do not list group information'
+complete -c logo-ls -s 'g' -d 'like -l, but do not list owner'
+complete -c logo-ls -s 'l' -d 'use a long listing format'
+complete -c logo-ls -s 'n' -l numeric-uid-gid -d 'like -l, but list numeric user and group IDs'
+complete -c logo-ls -l author -d 'with -l, print the author of each file'
+complete -c logo-ls -l octal-permissions -d 'in a long listing, also show permissions in octal, e.g. 0755'
+complete -c logo-ls -l permission-colors -d 'in a long listing, color each letter of the permissions by kind, for the --background'
+complete -c logo-ls -l audit -d 'flag risky files (world-writable, setuid, writable SSH keys, ...) and print a summary'
+complete -c logo-ls -l format -x -a 'vertical long single-column' -d 'layout: vertical (the default), long (-l), single-column (-1)'
+complete -c logo-ls -l color -x -a 'always auto never' -d 'colorize the output: always (the default), auto (only on a terminal) or never'
+complete -c logo-ls -l no-override -d 'disable loading the user icon override YAML'
+complete -c logo-ls -l override-file -r -F -d 'also load icon overrides from this YAML file; repeatable, later files win'
+complete -c logo-ls -l check-overrides -d 'report every problem in the icon override files and exit; exits 1 when there is one'
+complete -c logo-ls -l no-config -d 'ignore the config file and $LOGO_LS_OPTIONS'
+complete -c logo-ls -l no-project -d 'ignore .logo-ls.yaml project files'
+complete -c logo-ls -l debug-icons -d 'report on stderr which file supplied each icon'
+complete -c logo-ls -l sniff -d 'pick icons for unrecognised files from their first bytes'
+complete -c logo-ls -l background -x -a 'auto dark light' -d 'pick icon colors for a dark or light terminal background; auto (the default) reads $COLORFGBG'
+complete -c logo-ls -l link-chain -d 'in a long listing, show every hop of a symlink pointing to another symlink'
+complete -c logo-ls -s '@' -l xattr -d 'in a long listing, list the name and size of each extended attribute under the file'
+complete -c logo-ls -l xattr-values -d 'like --xattr, and also print each attribute\'s value'
+complete -c logo-ls -s 'Z' -l context -d 'print the SELinux security context of each file'
+complete -c logo-ls -l fs-type -d 'print the type of the file system each file is on, as a column in a long listing'
+complete -c logo-ls -l attributes -d 'in a long listing, show the Linux file attributes set by chattr, as lsattr does, and mark files with capabilities'
+complete -c logo-ls -l icon-set -x -a 'nerd emoji ascii' -d 'draw icons from a Nerd Font (the default), emoji or plain-text tags like [go]'
+complete -c logo-ls -l completion -x -a 'bash zsh fish powershell' -d 'print a completion script for the given shell and exit'
+complete -c logo-ls -l man -d 'print the roff man page and exit'
+complete -c logo-ls -l markdown-reference -d 'print the command reference as markdown and exit'
@@ -31,6 +31,42 @@ const (
 	LongListingNone
 )
 
+// ColorMode selects when ANSI colors are written.
+type ColorMode int
+
+const (
+	ColorAlways ColorMode = iota
+	ColorAuto
+	ColorNever
+)
+
+// DereferenceMode selects which symlinks are followed to show the file
+// they point to instead of the link itself.
+type DereferenceMode int
+
+const (
+	// DereferenceNone lists every symlink itself.
+	DereferenceNone DereferenceMode = iota
+	// DereferenceCommandLineDirs follows command-line symlinks that point
+	// to a directory, so its contents are listed.
+	DereferenceCommandLineDirs
+	// DereferenceCommandLine follows every command-line symlink (-H).
+	DereferenceCommandLine
+	// DereferenceAll follows every symlink, also inside directories and
+	// while recursing (-L).
+	DereferenceAll
+)
+
+// BackgroundMode selects the terminal background icon colors are picked
+// for.
+type BackgroundMode int
+
+const (
+	BackgroundAuto BackgroundMode = iota
+	BackgroundDark
+	BackgroundLight
+)
+
 // ExitCode is the process exit status the CLI reports.
 type ExitCode int
 
@@ -40,6 +76,16 @@ const (
 	CodeSerious
 )
 
+// ExitCodeDocs describes every exit code in ascending order, for the man page.
+var ExitCodeDocs = []struct {
+	Code        ExitCode
+	Description string
+}{
+	{CodeOk, "success"},
+	{CodeMinor, "minor problems, e.g. a subdirectory could not be read during -R"},
+	{CodeSerious, "serious trouble, e.g. a command-line argument is inaccessible or the options are invalid"},
+}
+
 // SetMinor sets the exit code to CodeMinor unless a more severe code is
 // already in effect.
 func (e *ExitCode) SetMinor() {
@@ -0,0 +1,139 @@
+package icons
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// IssueKind classifies a glyph that a Nerd Fonts v3 font cannot draw.
+type IssueKind int
+
+const (
+	// IssueRemoved is a codepoint of the v2 Material Design range, dropped
+	// in v3.
+	IssueRemoved IssueKind = iota
+	// IssueUnassigned is a private use codepoint outside every Nerd Fonts
+	// range.
+	IssueUnassigned
+)
+
+// Issue is a glyph that fails CheckGlyph.
+type Issue struct {
+	Source    string // "built-in" or the override's Provenance
+	Name      string // the icon's key in IconSet or IconDef, empty for overrides
+	Codepoint rune
+	Kind      IssueKind
+	// Replacement is the v3 codepoint from NerdFontMigration; 0 when there
+	// is none.
+	Replacement rune
+}
+
+func (i Issue) String() string {
+	what := i.Source
+	if i.Name != "" {
+		what += " " + i.Name
+	}
+	var msg string
+	switch i.Kind {
+	case IssueRemoved:
+		msg = fmt.Sprintf("%s: U+%04X was removed in Nerd Fonts v3", what, i.Codepoint)
+	default:
+		msg = fmt.Sprintf("%s: U+%04X is private use but not part of Nerd Fonts", what, i.Codepoint)
+	}
+	if i.Replacement != 0 {
+		msg += fmt.Sprintf("; use U+%04X", i.Replacement)
+	}
+	return msg
+}
+
+// privateUse reports whether r is in one of the Unicode private use areas.
+func privateUse(r rune) bool {
+	return (r >= 0xe000 && r <= 0xf8ff) ||
+		(r >= 0xf0000 && r <= 0xffffd) ||
+		(r >= 0x100000 && r <= 0x10fffd)
+}
+
+// CheckGlyph reports the first codepoint of glyph a Nerd Fonts v3 font
+// lacks. The v2 Material Design range spills out of the private use area,
+// so it is checked on its own; other codepoints outside the private use
+// areas are left to the font's regular glyphs and always pass.
+func CheckGlyph(glyph string) (Issue, bool) {
+	for _, r := range glyph {
+		if inNerdFont(r) {
+			continue
+		}
+		issue := Issue{Codepoint: r}
+		issue.Replacement, _ = NerdFontMigration(r)
+		switch {
+		case r >= NerdFontRemoved.First && r <= NerdFontRemoved.Last:
+			issue.Kind = IssueRemoved
+		case privateUse(r):
+			issue.Kind = IssueUnassigned
+		default:
+			continue
+		}
+		return issue, true
+	}
+	return Issue{}, false
+}
+
+func inNerdFont(r rune) bool {
+	for _, rg := range NerdFontRanges {
+		if r >= rg.First && r <= rg.Last {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckBuiltin checks the glyphs of IconSet and IconDef, ordered by name.
+func CheckBuiltin() []Issue {
+	var issues []Issue
+	for _, table := range []map[string]*IconInfo{IconSet, IconDef} {
+		for name, icon := range table {
+			if issue, ok := CheckGlyph(icon.Glyph); ok {
+				issue.Source, issue.Name = "built-in", name
+				issues = append(issues, issue)
+			}
+		}
+	}
+	slices.SortFunc(issues, func(a, b Issue) int { return cmp.Compare(a.Name, b.Name) })
+	return issues
+}
+
+// Check checks the glyphs set by o's entries and pattern rules, ordered by
+// file, section and key.
+func (o *Override) Check() []Issue {
+	if o == nil {
+		return nil
+	}
+	var froms []Provenance
+	glyphs := map[Provenance]string{}
+	add := func(e overrideEntry) {
+		// entries are stored under two keys; check each once
+		if _, seen := glyphs[e.from]; !seen && e.glyph != "" {
+			froms = append(froms, e.from)
+			glyphs[e.from] = e.glyph
+		}
+	}
+	for _, section := range []string{sectionDirs, sectionFiles, sectionSubExts, sectionExts} {
+		for _, e := range o.entries(section) {
+			add(e)
+		}
+	}
+	for _, r := range o.patterns {
+		add(r.entry)
+	}
+	slices.SortFunc(froms, func(a, b Provenance) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Section, b.Section), cmp.Compare(a.Key, b.Key))
+	})
+	var issues []Issue
+	for _, from := range froms {
+		if issue, ok := CheckGlyph(glyphs[from]); ok {
+			issue.Source = from.String()
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
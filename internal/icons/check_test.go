@@ -0,0 +1,78 @@
+package icons_test
+
+import (
+	"testing"
+
+	"github.com/canta2899/logo-ls/internal/icons"
+)
+
+// The built-in tables must only use glyphs a Nerd Fonts v3 font has.
+func TestCheckBuiltin(t *testing.T) {
+	for _, issue := range icons.CheckBuiltin() {
+		t.Error(issue)
+	}
+}
+
+func TestCheckGlyph(t *testing.T) {
+	tests := []struct {
+		glyph       string
+		ok          bool
+		kind        icons.IssueKind
+		replacement rune
+	}{
+		{glyph: "\U000f0241", ok: true},
+		{glyph: "", ok: true},
+		{glyph: "go", ok: true},
+		{glyph: "\U0001f4c1", ok: true},
+		// v2 Material Design glyphs move by a fixed offset
+		{glyph: "\uf853", kind: icons.IssueRemoved, replacement: 0xf0354},
+		{glyph: "\ufb25", kind: icons.IssueRemoved, replacement: 0xf0626},
+		{glyph: "\ufd46", kind: icons.IssueRemoved, replacement: 0xf0847},
+		{glyph: "\ue900", kind: icons.IssueUnassigned},
+	}
+	for _, tt := range tests {
+		issue, bad := icons.CheckGlyph(tt.glyph)
+		if bad == tt.ok {
+			t.Errorf("%+q: expected ok=%v, got issue %v", tt.glyph, tt.ok, issue)
+			continue
+		}
+		if bad && (issue.Kind != tt.kind || issue.Replacement != tt.replacement) {
+			t.Errorf("%+q: expected kind %d replacement %U, got %+v", tt.glyph, tt.kind, tt.replacement, issue)
+		}
+	}
+}
+
+func TestOverrideCheck(t *testing.T) {
+	ov, err := icons.ParseOverrides("icons.yaml", []byte(`
+extensions:
+  swf:
+    codepoint: "U+FB25"
+  go:
+    glyph: "G"
+files:
+  Makefile:
+    codepoint: "U+E900"
+patterns:
+  - glob: "*.test.ts"
+    codepoint: "U+F0668"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := ov.Check()
+	want := []string{
+		"icons.yaml (extensions: swf): U+FB25 was removed in Nerd Fonts v3; use U+F0626",
+		"icons.yaml (files: Makefile): U+E900 is private use but not part of Nerd Fonts",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d issues, got %v", len(want), got)
+	}
+	for i := range want {
+		if got[i].String() != want[i] {
+			t.Errorf("issue %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+	if (*icons.Override)(nil).Check() != nil {
+		t.Error("expected no issues for a nil override")
+	}
+}
@@ -0,0 +1,245 @@
+package icons
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Color is a color an icon can be drawn in: 24-bit RGB, or an entry of the
+// terminal's palette so the theme decides the shade.
+type Color struct {
+	// RGB is the color; for palette colors, the xterm default of the entry.
+	RGB [3]uint8
+	// Palette draws xterm-256 palette entry Index instead of RGB. Entries
+	// 0-15 are the ANSI colors.
+	Palette bool
+	Index   uint8
+}
+
+// Escape returns the SGR sequence selecting c as the foreground color.
+func (c Color) Escape() string {
+	switch {
+	case !c.Palette:
+		return fmt.Sprintf("\033[38;2;%03d;%03d;%03dm", c.RGB[0], c.RGB[1], c.RGB[2])
+	case c.Index < 8:
+		return fmt.Sprintf("\033[%dm", 30+c.Index)
+	case c.Index < 16:
+		return fmt.Sprintf("\033[%dm", 90+c.Index-8)
+	}
+	return fmt.Sprintf("\033[38;5;%dm", c.Index)
+}
+
+// Hex returns the color as #rrggbb.
+func (c Color) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.RGB[0], c.RGB[1], c.RGB[2])
+}
+
+// Paint holds override colors that IconInfo.Color cannot express: palette
+// colors, or separate colors for dark and light backgrounds.
+type Paint struct {
+	Dark, Light Color
+}
+
+// Background is the terminal background icon colors are picked for.
+type Background int
+
+const (
+	BackgroundDark Background = iota
+	BackgroundLight
+)
+
+// DetectBackground reads the background from the value of $COLORFGBG,
+// "fg;bg" with palette indices, as set by rxvt, Konsole and others. Light
+// grays and white are light backgrounds; anything else, including an unset
+// variable, is taken as dark.
+func DetectBackground(colorfgbg string) Background {
+	fields := strings.Split(colorfgbg, ";")
+	switch fields[len(fields)-1] {
+	case "7", "15":
+		return BackgroundLight
+	}
+	return BackgroundDark
+}
+
+// ansiNames are the names of palette entries 0-15, for "ansi:NAME".
+var ansiNames = []string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+	"bright-black", "bright-red", "bright-green", "bright-yellow",
+	"bright-blue", "bright-magenta", "bright-cyan", "bright-white",
+}
+
+// ansiRGB are xterm's default values of palette entries 0-15.
+var ansiRGB = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// paletteColor returns xterm-256 palette entry i with its default RGB.
+func paletteColor(i uint8) Color {
+	c := Color{Palette: true, Index: i}
+	switch {
+	case i < 16:
+		c.RGB = ansiRGB[i]
+	case i < 232:
+		// 6x6x6 color cube
+		level := func(n uint8) uint8 {
+			if n == 0 {
+				return 0
+			}
+			return 55 + 40*n
+		}
+		n := i - 16
+		c.RGB = [3]uint8{level(n / 36), level(n / 6 % 6), level(n % 6)}
+	default:
+		gray := 8 + 10*(i-232)
+		c.RGB = [3]uint8{gray, gray, gray}
+	}
+	return c
+}
+
+// colorSyntax lists the accepted color forms for error messages.
+const colorSyntax = "expected #RGB, #RRGGBB, rgb(), hsl(), a CSS color name, xterm:N, ansi:NAME or @icon"
+
+// parseColor parses a color written as #RGB or #RRGGBB, rgb(R, G, B),
+// hsl(H, S%, L%), a CSS/X11 color name, xterm:N for xterm-256 palette
+// entry N, ansi:NAME (e.g. ansi:bright-blue) or @icon, the color of a built-in icon
+// (e.g. @go).
+func parseColor(s string) (Color, error) {
+	raw := s
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch {
+	case strings.HasPrefix(s, "#"):
+		return parseHexColor(raw, s[1:])
+	case strings.HasPrefix(s, "@"):
+		if i, ok := IconSet[s[1:]]; ok {
+			return Color{RGB: i.Color}, nil
+		}
+		if i, ok := IconDef[s[1:]]; ok {
+			return Color{RGB: i.Color}, nil
+		}
+		return Color{}, fmt.Errorf("invalid color %q: no built-in icon named %q", raw, s[1:])
+	case strings.HasPrefix(s, "xterm:"):
+		n, err := strconv.ParseUint(s[len("xterm:"):], 10, 8)
+		if err != nil {
+			return Color{}, fmt.Errorf("invalid color %q: expected xterm: followed by an index in 0-255", raw)
+		}
+		return paletteColor(uint8(n)), nil
+	case strings.HasPrefix(s, "ansi:"):
+		for i, name := range ansiNames {
+			if strings.ReplaceAll(s[len("ansi:"):], "_", "-") == name {
+				return paletteColor(uint8(i)), nil
+			}
+		}
+		return Color{}, fmt.Errorf("invalid color %q: expected ansi: followed by one of %s", raw, strings.Join(ansiNames, ", "))
+	case strings.HasPrefix(s, "rgb(") || strings.HasPrefix(s, "rgba("):
+		return parseRGBFunc(raw, s)
+	case strings.HasPrefix(s, "hsl(") || strings.HasPrefix(s, "hsla("):
+		return parseHSLFunc(raw, s)
+	}
+	name := strings.NewReplacer(" ", "", "-", "", "_", "").Replace(s)
+	if rgb, ok := ColorNames[name]; ok {
+		return Color{RGB: rgb}, nil
+	}
+	// bare hex, as accepted before color names; digit-only values such as
+	// "808080" are hex too, palette entries need the xterm: prefix
+	if c, err := parseHexColor(raw, s); err == nil {
+		return c, nil
+	}
+	return Color{}, fmt.Errorf("invalid color %q: %s", raw, colorSyntax)
+}
+
+func parseHexColor(raw, s string) (Color, error) {
+	switch len(s) {
+	case 3:
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	case 6:
+	default:
+		return Color{}, fmt.Errorf("invalid color %q: expected #RGB or #RRGGBB", raw)
+	}
+	n, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid color %q: expected #RGB or #RRGGBB", raw)
+	}
+	return Color{RGB: [3]uint8{uint8(n >> 16), uint8(n >> 8), uint8(n)}}, nil
+}
+
+// colorArgs splits the arguments of a CSS color function, separated by
+// commas or spaces, dropping the alpha channel.
+func colorArgs(s string) ([]string, bool) {
+	open, close := strings.IndexByte(s, '('), strings.LastIndexByte(s, ')')
+	if close != len(s)-1 {
+		return nil, false
+	}
+	inner, _, _ := strings.Cut(s[open+1:close], "/")
+	args := strings.FieldsFunc(inner, func(r rune) bool { return r == ',' || r == ' ' })
+	if len(args) == 4 {
+		args = args[:3]
+	}
+	return args, len(args) == 3
+}
+
+// parseRGBFunc parses rgb(R, G, B) with 0-255 or percentage channels.
+func parseRGBFunc(raw, s string) (Color, error) {
+	args, ok := colorArgs(s)
+	if !ok {
+		return Color{}, fmt.Errorf("invalid color %q: expected rgb(R, G, B)", raw)
+	}
+	var c Color
+	for i, arg := range args {
+		pct, isPct := strings.CutSuffix(arg, "%")
+		v, err := strconv.ParseFloat(pct, 64)
+		if isPct {
+			v = v * 255 / 100
+		}
+		if err != nil || v < 0 || v > 255 {
+			return Color{}, fmt.Errorf("invalid color %q: channel %q is not in 0-255 or 0%%-100%%", raw, args[i])
+		}
+		c.RGB[i] = uint8(math.Round(v))
+	}
+	return c, nil
+}
+
+// parseHSLFunc parses hsl(H, S%, L%), with the hue in degrees.
+func parseHSLFunc(raw, s string) (Color, error) {
+	args, ok := colorArgs(s)
+	if !ok {
+		return Color{}, fmt.Errorf("invalid color %q: expected hsl(H, S%%, L%%)", raw)
+	}
+	h, err := strconv.ParseFloat(strings.TrimSuffix(args[0], "deg"), 64)
+	if err != nil {
+		return Color{}, fmt.Errorf("invalid color %q: hue %q is not a number", raw, args[0])
+	}
+	var sl [2]float64
+	for i, arg := range args[1:] {
+		v, err := strconv.ParseFloat(strings.TrimSuffix(arg, "%"), 64)
+		if err != nil || v < 0 || v > 100 {
+			return Color{}, fmt.Errorf("invalid color %q: %q is not in 0%%-100%%", raw, arg)
+		}
+		sl[i] = v / 100
+	}
+	h = math.Mod(math.Mod(h, 360)+360, 360)
+	chroma := (1 - math.Abs(2*sl[1]-1)) * sl[0]
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g = chroma, x
+	case h < 120:
+		r, g = x, chroma
+	case h < 180:
+		g, b = chroma, x
+	case h < 240:
+		g, b = x, chroma
+	case h < 300:
+		r, b = x, chroma
+	default:
+		r, b = chroma, x
+	}
+	m := sl[1] - chroma/2
+	channel := func(v float64) uint8 { return uint8(math.Round((v + m) * 255)) }
+	return Color{RGB: [3]uint8{channel(r), channel(g), channel(b)}}, nil
+}
@@ -0,0 +1,143 @@
+package icons_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/canta2899/logo-ls/internal/icons"
+)
+
+// colorOf parses a one-entry override setting the color of .go files and
+// returns the resolved icon.
+func colorOf(t *testing.T, color string) (*icons.IconInfo, error) {
+	t.Helper()
+	ov, err := icons.ParseOverrides("icons.yaml", []byte("extensions:\n  go:\n    color: '"+color+"'\n"))
+	if err != nil {
+		return nil, err
+	}
+	return icons.ResolveWith(ov, "main", ".go", ""), nil
+}
+
+func TestOverrideColorSyntax(t *testing.T) {
+	tests := []struct {
+		in     string
+		rgb    [3]uint8
+		escape string
+	}{
+		{"#f80", [3]uint8{255, 136, 0}, "\033[38;2;255;136;000m"},
+		{"#FF8800", [3]uint8{255, 136, 0}, "\033[38;2;255;136;000m"},
+		{"ff8800", [3]uint8{255, 136, 0}, "\033[38;2;255;136;000m"},
+		{"rgb(255, 136, 0)", [3]uint8{255, 136, 0}, "\033[38;2;255;136;000m"},
+		{"rgb(100% 0% 50% / 0.5)", [3]uint8{255, 0, 128}, "\033[38;2;255;000;128m"},
+		{"rgba(1,2,3,0.5)", [3]uint8{1, 2, 3}, "\033[38;2;001;002;003m"},
+		{"hsl(120, 100%, 25%)", [3]uint8{0, 128, 0}, "\033[38;2;000;128;000m"},
+		{"hsl(-120deg 100% 50%)", [3]uint8{0, 0, 255}, "\033[38;2;000;000;255m"},
+		{"RebeccaPurple", [3]uint8{102, 51, 153}, "\033[38;2;102;051;153m"},
+		{"light-sea green", [3]uint8{32, 178, 170}, "\033[38;2;032;178;170m"},
+		{"@rust", icons.IconSet["rust"].Color, icons.IconSet["rust"].GetColor()},
+		{"@dir", icons.IconDef["dir"].Color, icons.IconDef["dir"].GetColor()},
+		{"ansi:red", [3]uint8{205, 0, 0}, "\033[31m"},
+		{"ansi:bright_blue", [3]uint8{92, 92, 255}, "\033[94m"},
+		{"xterm:3", [3]uint8{205, 205, 0}, "\033[33m"},
+		{"xterm:208", [3]uint8{255, 135, 0}, "\033[38;5;208m"},
+		{"XTERM:244", [3]uint8{128, 128, 128}, "\033[38;5;244m"},
+		// digit-only values are bare hex, never palette indices
+		{"808080", [3]uint8{128, 128, 128}, "\033[38;2;128;128;128m"},
+		{"333", [3]uint8{51, 51, 51}, "\033[38;2;051;051;051m"},
+		{"123", [3]uint8{17, 34, 51}, "\033[38;2;017;034;051m"},
+		{"000", [3]uint8{0, 0, 0}, "\033[38;2;000;000;000m"},
+	}
+	for _, tt := range tests {
+		got, err := colorOf(t, tt.in)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.in, err)
+			continue
+		}
+		if got.Color != tt.rgb || got.GetColor() != tt.escape {
+			t.Errorf("%q: expected %v %q, got %v %q", tt.in, tt.rgb, tt.escape, got.Color, got.GetColor())
+		}
+	}
+}
+
+func TestOverrideColorErrors(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"#12", `invalid color "#12": expected #RGB or #RRGGBB`},
+		{"rgb(1, 2)", `invalid color "rgb(1, 2)": expected rgb(R, G, B)`},
+		{"rgb(256, 0, 0)", `channel "256" is not in 0-255`},
+		{"hsl(0, 150%, 50%)", `"150%" is not in 0%-100%`},
+		{"xterm:256", `invalid color "xterm:256": expected xterm: followed by an index in 0-255`},
+		{"2080", `invalid color "2080": expected #RGB, #RRGGBB, rgb(), hsl(), a CSS color name, xterm:N, ansi:NAME or @icon`},
+		{"ansi:orange", `expected ansi: followed by one of black, red`},
+		{"@nope", `no built-in icon named "nope"`},
+		{"blurple", `invalid color "blurple": expected #RGB`},
+	}
+	for _, tt := range tests {
+		_, err := colorOf(t, tt.in)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: expected error containing %q, got %v", tt.in, tt.want, err)
+		}
+	}
+}
+
+func TestOverrideColorPerBackground(t *testing.T) {
+	ov, err := icons.ParseOverrides("icons.yaml", []byte(`
+extensions:
+  go:
+    color: {dark: "#ffffff", light: navy}
+  rs:
+    color: {light: ansi:black}
+  py:
+    color: ansi:blue
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := icons.ResolveWith(ov, "main", ".go", "")
+	if got.ColorFor(icons.BackgroundDark).Hex() != "#ffffff" || got.ColorFor(icons.BackgroundLight).Hex() != "#000080" {
+		t.Errorf("go: expected #ffffff on dark and #000080 on light, got %+v", got.Paint)
+	}
+	if got.Color != [3]uint8{255, 255, 255} {
+		t.Errorf("go: expected Color to hold the dark color, got %v", got.Color)
+	}
+
+	// Intent: a background the entry leaves out keeps the built-in color.
+	got = icons.ResolveWith(ov, "main", ".rs", "")
+	if base := icons.Resolve("main", ".rs", ""); got.Color != base.Color || got.GetColor() != base.GetColor() {
+		t.Errorf("rs: expected the built-in dark color, got %v", got.Color)
+	}
+	if got.ColorOn(icons.BackgroundLight) != "\033[30m" {
+		t.Errorf("rs: expected the ANSI black escape on light, got %q", got.ColorOn(icons.BackgroundLight))
+	}
+
+	got = icons.ResolveWith(ov, "main", ".py", "")
+	if got.ColorOn(icons.BackgroundDark) != "\033[34m" || got.ColorOn(icons.BackgroundLight) != "\033[34m" {
+		t.Errorf("py: expected the ANSI blue escape on both backgrounds, got %+v", got.Paint)
+	}
+	// Intent: executables keep their green whatever the override says.
+	if exe := got.AsExecutable(); exe.ColorFor(icons.BackgroundLight) != (icons.Color{RGB: [3]uint8{76, 175, 80}}) {
+		t.Errorf("expected executables to stay green, got %q", exe.ColorOn(icons.BackgroundLight))
+	}
+
+	_, err = icons.ParseOverrides("icons.yaml", []byte("extensions:\n  go:\n    color: {dim: red}\n  rs:\n    color: {}\n"))
+	want := "icons.yaml:3:13: extensions: \"go\": color: unknown key \"dim\"\n" +
+		"icons.yaml:3:12: extensions: \"go\": color: expected dark, light, or both\n" +
+		"icons.yaml:5:12: extensions: \"rs\": color: expected dark, light, or both"
+	if err == nil || err.Error() != want {
+		t.Errorf("expected\n%s\ngot\n%v", want, err)
+	}
+}
+
+func TestDetectBackground(t *testing.T) {
+	for in, want := range map[string]icons.Background{
+		"":             icons.BackgroundDark,
+		"15;0":         icons.BackgroundDark,
+		"0;15":         icons.BackgroundLight,
+		"0;7":          icons.BackgroundLight,
+		"0;default;15": icons.BackgroundLight,
+		"7;8":          icons.BackgroundDark,
+	} {
+		if got := icons.DetectBackground(in); got != want {
+			t.Errorf("DetectBackground(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
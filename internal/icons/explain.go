@@ -0,0 +1,152 @@
+package icons
+
+import (
+	"slices"
+	"strings"
+)
+
+// Pseudo-sections for the mappings that do not come from a YAML section.
+const (
+	sectionPaths   = "paths"   // the built-in path rules
+	sectionDefault = "default" // the IconDef fallbacks
+)
+
+// Mapping is one entry of the icon tables, with the overrides applied.
+type Mapping struct {
+	// Section is the YAML section of the entry, "paths" for a built-in
+	// path rule or "default" for a fallback icon.
+	Section string
+	Key     string // the entry's key, or the rule's pattern
+	Icon    *IconInfo
+	// From is the override entry that added or changed the mapping; its
+	// File is empty for built-in mappings.
+	From Provenance
+}
+
+// Source returns the file the mapping was read from, or "built-in".
+func (m Mapping) Source() string {
+	if m.From.File == "" {
+		return "built-in"
+	}
+	return m.From.File
+}
+
+// Explanation tells how an entry's icon was resolved.
+type Explanation struct {
+	Icon *IconInfo // as ResolveIn returns it
+	// Builtin is the built-in mapping the icon started from: a path rule,
+	// a table entry, or a default icon when nothing matched.
+	Builtin Mapping
+	// Override is the override entry applied over Builtin; Overridden is
+	// false when there is none.
+	Override   Provenance
+	Overridden bool
+}
+
+// Explain resolves an icon like ResolveIn and reports which mappings
+// supplied it.
+func Explain(ov *Override, dir, name, fileExt, indicator string) Explanation {
+	x := Explanation{Builtin: matchBuiltin(ov, dir, name, fileExt, indicator)}
+	base := x.Builtin.Icon
+	if entry, ok := ov.lookupEntry(dir, name, fileExt, indicator); ok {
+		base = entry.apply(base)
+		x.Override, x.Overridden = entry.from, true
+	}
+	x.Icon = applyIndicator(base, indicator)
+	return x
+}
+
+// ExplainSpecial reports the IconDef icon key drawn for a special file,
+// such as a device or a setuid program, regardless of its name.
+func ExplainSpecial(key string) Explanation {
+	m := Mapping{Section: sectionDefault, Key: key, Icon: IconDef[key]}
+	return Explanation{Icon: m.Icon, Builtin: m}
+}
+
+// Mappings lists every mapping in lookup order: the path rules, then the
+// files, patterns, sub-extensions, extensions and directories. Keys are
+// sorted within a table; rules keep their order. Entries removed by ov are
+// left out.
+func Mappings(ov *Override) []Mapping {
+	var out []Mapping
+	for _, r := range IconPath {
+		out = append(out, Mapping{Section: sectionPaths, Key: r.Dir + "/" + r.Glob, Icon: r.Icon})
+	}
+	for _, section := range []string{sectionFiles, sectionPatterns, sectionSubExts, sectionExts, sectionDirs} {
+		if section == sectionPatterns {
+			if ov != nil {
+				for _, r := range ov.patterns {
+					// color-only rules are shown over the generic file icon
+					out = append(out, Mapping{Section: section, Key: r.entry.from.Key, Icon: r.entry.apply(IconDef["file"]), From: r.entry.from})
+				}
+			}
+			continue
+		}
+		out = append(out, tableMappings(ov, section)...)
+	}
+	return out
+}
+
+// tableMappings merges the built-in table of section with ov's entries.
+func tableMappings(ov *Override, section string) []Mapping {
+	builtin := builtinTables[section]
+	entries := map[string]overrideEntry{}
+	for key, e := range ov.entries(section) {
+		// entries are stored under two keys; keep the one lookups use
+		if key == lookupKeyOf(section, e.from.Key) {
+			entries[key] = e
+		}
+	}
+	keys := make([]string, 0, len(builtin)+len(entries))
+	for key := range builtin {
+		keys = append(keys, key)
+	}
+	for key := range entries {
+		if _, ok := builtin[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	// keys new to the table apply over the fallback icon
+	fallback := IconDef["file"]
+	if section == sectionDirs {
+		fallback = IconDef["dir"]
+	}
+	out := make([]Mapping, 0, len(keys))
+	for _, key := range keys {
+		m := Mapping{Section: section, Key: key, Icon: builtin[key]}
+		if e, ok := entries[key]; ok {
+			if e.unset {
+				continue
+			}
+			if m.Icon == nil {
+				m.Icon = fallback
+			}
+			m.Icon, m.From = e.apply(m.Icon), e.from
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// lookupKeyOf returns the key lookups use for an entry written as key.
+func lookupKeyOf(section, key string) string {
+	key = strings.ToLower(key)
+	if section == sectionExts {
+		key = strings.TrimPrefix(key, ".")
+	}
+	return key
+}
+
+// Search returns the mappings whose key or icon name contains term,
+// ignoring case.
+func Search(ov *Override, term string) []Mapping {
+	term = strings.ToLower(term)
+	var out []Mapping
+	for _, m := range Mappings(ov) {
+		if strings.Contains(strings.ToLower(m.Key), term) || strings.Contains(strings.ToLower(m.Icon.Name), term) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+package icons_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/canta2899/logo-ls/internal/icons"
+)
+
+func TestExplain(t *testing.T) {
+	ov, err := icons.ParseOverrides("icons.yaml", []byte(`
+extensions:
+  go:
+    color: "#ff0000"
+files:
+  package.json:
+    unset: true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	x := icons.Explain(ov, "/src", "main", ".go", "")
+	if x.Builtin.Section != "extensions" || x.Builtin.Key != "go" || x.Builtin.Source() != "built-in" {
+		t.Errorf("expected the built-in go extension, got %+v", x.Builtin)
+	}
+	if !x.Overridden || x.Override.String() != "icons.yaml (extensions: go)" {
+		t.Errorf("expected the go override, got %v %v", x.Overridden, x.Override)
+	}
+	if x.Icon.Color != [3]uint8{255, 0, 0} || x.Icon.Glyph != icons.IconExt["go"].Glyph {
+		t.Errorf("expected the recolored go icon, got %+v", x.Icon)
+	}
+	if got := icons.ResolveIn(ov, "/src", "main", ".go", ""); *got != *x.Icon {
+		t.Errorf("expected Explain to agree with ResolveIn: %+v != %+v", got, x.Icon)
+	}
+
+	// the unset file name falls through to the extension
+	if x := icons.Explain(ov, "", "package", ".json", ""); x.Builtin.Section != "extensions" || x.Builtin.Key != "json" {
+		t.Errorf("expected the json extension, got %+v", x.Builtin)
+	}
+	if x := icons.Explain(nil, "/repo/.github/workflows", "ci", ".yml", ""); x.Builtin.Section != "paths" || x.Builtin.Key != ".github/workflows/*.yml" {
+		t.Errorf("expected the workflows path rule, got %+v", x.Builtin)
+	}
+	if x := icons.Explain(nil, "", "unknown", ".zzz", "*"); x.Builtin.Section != "default" || x.Builtin.Key != "file" || !x.Icon.IsExecutable {
+		t.Errorf("expected the executable default icon, got %+v", x)
+	}
+}
+
+func TestMappings(t *testing.T) {
+	ov, err := icons.ParseOverrides("icons.yaml", []byte(`
+extensions:
+  .mydsl:
+    glyph: "D"
+files:
+  go.mod:
+    unset: true
+patterns:
+  - glob: "*.test.ts"
+    color: "#00ff00"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := map[string]icons.Mapping{}
+	for _, m := range icons.Mappings(ov) {
+		key := m.Section + ":" + m.Key
+		if _, dup := found[key]; dup {
+			t.Errorf("duplicate mapping %s", key)
+		}
+		found[key] = m
+	}
+	if m, ok := found["extensions:mydsl"]; !ok || m.Icon.Glyph != "D" || m.Source() != "icons.yaml" {
+		t.Errorf("expected the added extension, got %+v", m)
+	}
+	if m := found["extensions:mydsl"]; m.Icon.Color != icons.IconDef["file"].Color {
+		t.Errorf("expected a new key to keep the file color, got %v", m.Icon.Color)
+	}
+	if _, ok := found["files:go.mod"]; ok {
+		t.Error("expected the unset go.mod to be left out")
+	}
+	if m, ok := found["patterns:*.test.ts"]; !ok || m.Icon.Color != [3]uint8{0, 255, 0} {
+		t.Errorf("expected the pattern rule, got %+v", m)
+	}
+	if m, ok := found["extensions:go"]; !ok || m.Source() != "built-in" {
+		t.Errorf("expected the built-in go extension, got %+v", m)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ms := icons.Search(nil, "Docker")
+	if len(ms) == 0 {
+		t.Fatal("expected docker mappings")
+	}
+	for _, m := range ms {
+		if !strings.Contains(m.Key, "docker") && !strings.Contains(m.Icon.Name, "docker") {
+			t.Errorf("%s %s (%s) does not match", m.Section, m.Key, m.Icon.Name)
+		}
+	}
+	if ms := icons.Search(nil, "no-such-icon"); len(ms) != 0 {
+		t.Errorf("expected no mappings, got %v", ms)
+	}
+}
@@ -1,13 +1,56 @@
 package icons
 
-import "fmt"
-
 // IconInfo is one entry in the icon set: a nerd-font glyph and an RGB color.
 // IsExecutable swaps the color to a green hue for files marked executable.
 type IconInfo struct {
 	Glyph        string
 	Color        [3]uint8 // RGB; (0,0,0) is black
 	IsExecutable bool
+	// Name is the icon's key in IconSet or IconDef, used to look up its
+	// glyph in the other glyph sets. Empty for glyphs set by an override.
+	Name string
+	// Paint, set by overrides only, replaces Color with a palette color or
+	// a color per background. Color then holds the dark one.
+	Paint *Paint
+}
+
+func init() {
+	for name, i := range IconSet {
+		i.Name = name
+	}
+	for name, i := range IconDef {
+		i.Name = name
+	}
+}
+
+// GlyphSet selects the table glyphs are drawn from.
+type GlyphSet int
+
+const (
+	GlyphsNerd GlyphSet = iota
+	GlyphsEmoji
+	GlyphsASCII
+)
+
+// GlyphIn returns the icon's glyph in set. Glyphs set by an override are
+// used as they are in every set.
+func (i *IconInfo) GlyphIn(set GlyphSet) string {
+	if i == nil {
+		return ""
+	}
+	var table map[string]string
+	switch set {
+	case GlyphsEmoji:
+		table = IconEmoji
+	case GlyphsASCII:
+		table = IconASCII
+	default:
+		return i.Glyph
+	}
+	if g, ok := table[i.Name]; ok {
+		return g
+	}
+	return i.Glyph
 }
 
 // GetGlyph returns the icon glyph, or "" when the receiver is nil.
@@ -18,16 +61,46 @@ func (i *IconInfo) GetGlyph() string {
 	return i.Glyph
 }
 
-// GetColor returns the ANSI 24-bit color escape for the icon. Executables
-// always render in a fixed green regardless of the configured Color.
+// GetColor returns the ANSI color escape for the icon on a dark
+// background. Executables always render in a fixed green regardless of the
+// configured Color.
 func (i *IconInfo) GetColor() string {
+	return i.ColorOn(BackgroundDark)
+}
+
+// ColorOn returns the ANSI color escape for the icon on background bg, or
+// "" when the receiver is nil.
+func (i *IconInfo) ColorOn(bg Background) string {
 	if i == nil {
 		return ""
 	}
 	if i.IsExecutable {
 		return "\033[38;2;76;175;080m"
 	}
-	return fmt.Sprintf("\033[38;2;%03d;%03d;%03dm", i.Color[0], i.Color[1], i.Color[2])
+	return i.ColorFor(bg).Escape()
+}
+
+// ColorFor returns the icon's color on background bg.
+func (i *IconInfo) ColorFor(bg Background) Color {
+	if i == nil {
+		return Color{}
+	}
+	if i.IsExecutable {
+		return Color{RGB: [3]uint8{76, 175, 80}}
+	}
+	if bg == BackgroundLight {
+		return i.paint().Light
+	}
+	return i.paint().Dark
+}
+
+// paint returns the icon's colors per background, ignoring IsExecutable.
+func (i *IconInfo) paint() Paint {
+	if i.Paint != nil {
+		return *i.Paint
+	}
+	c := Color{RGB: i.Color}
+	return Paint{Dark: c, Light: c}
 }
 
 // AsExecutable returns a copy of the icon with IsExecutable set, leaving the
@@ -0,0 +1,53 @@
+package icons_test
+
+import (
+	"testing"
+
+	"github.com/canta2899/logo-ls/internal/icons"
+)
+
+// Verifies the emoji and ascii tables cover every built-in icon.
+func TestGlyphSetsAreComplete(t *testing.T) {
+	tables := map[string]map[string]string{"emoji": icons.IconEmoji, "ascii": icons.IconASCII}
+	for set, table := range tables {
+		for _, m := range []map[string]*icons.IconInfo{icons.IconSet, icons.IconDef} {
+			for name := range m {
+				if table[name] == "" {
+					t.Errorf("%s: no glyph for %q", set, name)
+				}
+			}
+		}
+		for name := range table {
+			if icons.IconSet[name] == nil && icons.IconDef[name] == nil {
+				t.Errorf("%s: %q is not a built-in icon", set, name)
+			}
+		}
+	}
+}
+
+func TestGlyphIn(t *testing.T) {
+	goIcon := icons.Resolve("main", ".go", "")
+	if got := goIcon.GlyphIn(icons.GlyphsNerd); got != goIcon.Glyph {
+		t.Errorf("nerd: expected %q, got %q", goIcon.Glyph, got)
+	}
+	if got := goIcon.GlyphIn(icons.GlyphsASCII); got != "[go]" {
+		t.Errorf("ascii: expected [go], got %q", got)
+	}
+	if got := icons.Resolve("src", "", "/").GlyphIn(icons.GlyphsEmoji); got != icons.IconEmoji["dir"] {
+		t.Errorf("emoji: expected the dir emoji, got %q", got)
+	}
+	// executables and color-only overrides keep the icon's name
+	if got := icons.Resolve("main", ".go", "*").GlyphIn(icons.GlyphsASCII); got != "[go]" {
+		t.Errorf("ascii: expected an executable to keep [go], got %q", got)
+	}
+	ov, err := icons.ParseOverrides("icons.yaml", []byte("extensions:\n  go: {color: \"#ff0000\"}\n  rs: {glyph: R}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := icons.ResolveWith(ov, "main", ".go", "").GlyphIn(icons.GlyphsASCII); got != "[go]" {
+		t.Errorf("ascii: expected a recolored icon to keep [go], got %q", got)
+	}
+	if got := icons.ResolveWith(ov, "main", ".rs", "").GlyphIn(icons.GlyphsASCII); got != "R" {
+		t.Errorf("ascii: expected an override glyph to be kept, got %q", got)
+	}
+}
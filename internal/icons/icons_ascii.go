@@ -0,0 +1,270 @@
+package icons
+
+// IconASCII holds the plain-text tags of the ascii icon set, keyed by the
+// names in IconSet and IconDef. IconDef's exe shares the IconSet entry.
+// This file is data-only.
+var IconASCII = map[string]string{
+	// IconSet
+	"3d":               "[3d]",
+	"actionscript":     "[as]",
+	"alpine":           "[alpine]",
+	"android":          "[droid]",
+	"apiblueprint":     "[apib]",
+	"applescript":      "[ascpt]",
+	"arch":             "[arch]",
+	"arduino":          "[ino]",
+	"asciidoc":         "[adoc]",
+	"assembly":         "[asm]",
+	"audio":            "[audio]",
+	"authors":          "[authors]",
+	"autohotkey":       "[ahk]",
+	"azure":            "[azure]",
+	"azure-pipelines":  "[azure]",
+	"babel":            "[babel]",
+	"bitbucket":        "[bb]",
+	"blink":            "[blink]",
+	"bower":            "[bower]",
+	"bun":              "[bun]",
+	"c":                "[c]",
+	"cake":             "[cake]",
+	"certificate":      "[cert]",
+	"centOS":           "[centos]",
+	"changelog":        "[chlog]",
+	"clojure":          "[clj]",
+	"cmake":            "[cmake]",
+	"coconut":          "[coco]",
+	"code-climate":     "[cc]",
+	"codecov":          "[codecov]",
+	"codeowners":       "[owners]",
+	"coffee":           "[coffee]",
+	"command":          "[cmd]",
+	"commitlint":       "[lint]",
+	"conduct":          "[coc]",
+	"console":          "[sh]",
+	"contributing":     "[contrib]",
+	"cpp":              "[c++]",
+	"credits":          "[credits]",
+	"csharp":           "[c#]",
+	"css":              "[css]",
+	"css-map":          "[map]",
+	"d":                "[d]",
+	"dart":             "[dart]",
+	"database":         "[db]",
+	"debian":           "[deb]",
+	"denizenscript":    "[dsc]",
+	"dhall":            "[dhall]",
+	"disc":             "[disc]",
+	"django":           "[django]",
+	"docker":           "[docker]",
+	"document":         "[doc]",
+	"dune":             "[dune]",
+	"edge":             "[edge]",
+	"editorconfig":     "[editcfg]",
+	"ejs":              "[ejs]",
+	"elixir":           "[ex]",
+	"elm":              "[elm]",
+	"email":            "[mail]",
+	"erlang":           "[erl]",
+	"eslint":           "[eslint]",
+	"exe":              "[exe]",
+	"fastlane":         "[fastlane]",
+	"favicon":          "[ico]",
+	"fedora":           "[fedora]",
+	"firebase":         "[fbase]",
+	"flash":            "[swf]",
+	"font":             "[font]",
+	"fortran":          "[f90]",
+	"freebsd":          "[bsd]",
+	"fsharp":           "[f#]",
+	"gatsby":           "[gatsby]",
+	"gcp":              "[gcp]",
+	"gemfile":          "[gem]",
+	"gentoo":           "[gentoo]",
+	"gnu":              "[gnu]",
+	"git":              "[git]",
+	"github-actions":   "[actions]",
+	"gitlab":           "[gitlab]",
+	"go":               "[go]",
+	"go-mod":           "[gomod]",
+	"go-test":          "[gotest]",
+	"godot":            "[godot]",
+	"godot-assets":     "[godot]",
+	"gradle":           "[gradle]",
+	"graphql":          "[gql]",
+	"groovy":           "[groovy]",
+	"grunt":            "[grunt]",
+	"gulp":             "[gulp]",
+	"h":                "[h]",
+	"handlebars":       "[hbs]",
+	"haskell":          "[hs]",
+	"haxe":             "[hx]",
+	"helm":             "[helm]",
+	"heroku":           "[heroku]",
+	"hpp":              "[h++]",
+	"html":             "[html]",
+	"http":             "[http]",
+	"husky":            "[husky]",
+	"i18n":             "[i18n]",
+	"image":            "[img]",
+	"ionic":            "[ionic]",
+	"java":             "[java]",
+	"javascript":       "[js]",
+	"javascript-map":   "[map]",
+	"jenkins":          "[jenkins]",
+	"jest":             "[jest]",
+	"jinja":            "[jinja]",
+	"json":             "[json]",
+	"julia":            "[jl]",
+	"karma":            "[karma]",
+	"key":              "[key]",
+	"kotlin":           "[kt]",
+	"kubernetes":       "[k8s]",
+	"laravel":          "[laravel]",
+	"less":             "[less]",
+	"lib":              "[lib]",
+	"linux":            "[linux]",
+	"liquid":           "[liquid]",
+	"lock":             "[lock]",
+	"log":              "[log]",
+	"lua":              "[lua]",
+	"makefile":         "[make]",
+	"manjaro":          "[manjaro]",
+	"markdown":         "[md]",
+	"markojs":          "[marko]",
+	"mdx":              "[mdx]",
+	"merlin":           "[merlin]",
+	"midi":             "[midi]",
+	"mint":             "[mint]",
+	"mjml":             "[mjml]",
+	"mocha":            "[mocha]",
+	"modernizr":        "[mdrnzr]",
+	"moonscript":       "[moon]",
+	"mxml":             "[mxml]",
+	"mysql":            "[sql]",
+	"nim":              "[nim]",
+	"nix":              "[nix]",
+	"nodejs":           "[node]",
+	"npm":              "[npm]",
+	"nuget":            "[nuget]",
+	"nuxt":             "[nuxt]",
+	"ocaml":            "[ml]",
+	"opam":             "[opam]",
+	"opensuse":         "[suse]",
+	"pascal":           "[pas]",
+	"pawn":             "[pawn]",
+	"pdf":              "[pdf]",
+	"perl":             "[pl]",
+	"php":              "[php]",
+	"postcss":          "[pcss]",
+	"postgresql":       "[pgsql]",
+	"powerpoint":       "[ppt]",
+	"powershell":       "[ps1]",
+	"prettier":         "[prettier]",
+	"prisma":           "[prisma]",
+	"prolog":           "[prolog]",
+	"protractor":       "[ptor]",
+	"pug":              "[pug]",
+	"puppet":           "[pp]",
+	"purescript":       "[purs]",
+	"python":           "[py]",
+	"python-misc":      "[py]",
+	"qsharp":           "[q#]",
+	"r":                "[r]",
+	"raml":             "[raml]",
+	"raspberry-pi":     "[rpi]",
+	"razor":            "[razor]",
+	"react":            "[react]",
+	"react_ts":         "[tsx]",
+	"readme":           "[readme]",
+	"redhat":           "[redhat]",
+	"roadmap":          "[roadmap]",
+	"robot":            "[robot]",
+	"rollup":           "[rollup]",
+	"routing":          "[route]",
+	"ruby":             "[rb]",
+	"rust":             "[rs]",
+	"sass":             "[sass]",
+	"scheme":           "[scm]",
+	"semantic-release": "[release]",
+	"settings":         "[conf]",
+	"shaderlab":        "[shader]",
+	"sketch":           "[sketch]",
+	"slim":             "[slim]",
+	"smarty":           "[tpl]",
+	"solidity":         "[sol]",
+	"sqlite":           "[sqlite]",
+	"storybook":        "[story]",
+	"stryker":          "[stryker]",
+	"stylelint":        "[lint]",
+	"stylus":           "[styl]",
+	"sublime":          "[subl]",
+	"svelte":           "[svelte]",
+	"svg":              "[svg]",
+	"swc":              "[swc]",
+	"swift":            "[swift]",
+	"table":            "[csv]",
+	"tailwindcss":      "[tw]",
+	"tcl":              "[tcl]",
+	"terraform":        "[tf]",
+	"test-js":          "[test]",
+	"test-jsx":         "[test]",
+	"test-ts":          "[test]",
+	"tex":              "[tex]",
+	"todo":             "[todo]",
+	"travis":           "[travis]",
+	"tune":             "[tune]",
+	"twig":             "[twig]",
+	"typescript":       "[ts]",
+	"typescript-def":   "[d.ts]",
+	"typst":            "[typ]",
+	"ubuntu":           "[ubuntu]",
+	"url":              "[url]",
+	"vagrant":          "[vagrant]",
+	"vala":             "[vala]",
+	"vercel":           "[vercel]",
+	"verilog":          "[v]",
+	"video":            "[video]",
+	"vim":              "[vim]",
+	"virtual":          "[vr]",
+	"visualstudio":     "[vs]",
+	"vscode":           "[vscode]",
+	"vue":              "[vue]",
+	"vue-config":       "[vue]",
+	"webpack":          "[webpack]",
+	"word":             "[doc]",
+	"xaml":             "[xaml]",
+	"xml":              "[xml]",
+	"yaml":             "[yaml]",
+	"yang":             "[yang]",
+	"yarn":             "[yarn]",
+	"zig":              "[zig]",
+	"zip":              "[zip]",
+	"dir-config":       "[dir]",
+	"dir-controller":   "[dir]",
+	"dir-download":     "[dir]",
+	"dir-environment":  "[dir]",
+	"dir-git":          "[dir]",
+	"dir-github":       "[dir]",
+	"dir-images":       "[dir]",
+	"dir-import":       "[dir]",
+	"dir-include":      "[dir]",
+	"dir-npm":          "[dir]",
+	"dir-secure":       "[dir]",
+	"dir-upload":       "[dir]",
+
+	// IconDef
+	"dir":        "[dir]",
+	"diropen":    "[dir]",
+	"file":       "[file]",
+	"hiddendir":  "[dir]",
+	"hiddenfile": "[file]",
+
+	"blockdevice": "[blk]",
+	"chardevice":  "[chr]",
+	"pipe":        "[pipe]",
+	"socket":      "[sock]",
+	"setuid":      "[suid]",
+	"setgid":      "[sgid]",
+	"brokenlink":  "[broken]",
+	"mount":       "[mnt]",
+}
@@ -0,0 +1,271 @@
+package icons
+
+// IconEmoji holds the glyphs of the emoji icon set, keyed by the names in
+// IconSet and IconDef. Every glyph has emoji presentation, so terminals
+// draw it two cells wide. IconDef's exe shares the IconSet entry. This file
+// is data-only.
+var IconEmoji = map[string]string{
+	// IconSet
+	"3d":               "🧊",
+	"actionscript":     "📜",
+	"alpine":           "🗻",
+	"android":          "🤖",
+	"apiblueprint":     "📘",
+	"applescript":      "🍎",
+	"arch":             "🐧",
+	"arduino":          "🔌",
+	"asciidoc":         "📝",
+	"assembly":         "🔩",
+	"audio":            "🎵",
+	"authors":          "👥",
+	"autohotkey":       "🎹",
+	"azure":            "🔷",
+	"azure-pipelines":  "🔷",
+	"babel":            "🐠",
+	"bitbucket":        "🪣",
+	"blink":            "🎬",
+	"bower":            "🐦",
+	"bun":              "🥟",
+	"c":                "🔵",
+	"cake":             "🎂",
+	"certificate":      "📜",
+	"centOS":           "🟣",
+	"changelog":        "📰",
+	"clojure":          "🌀",
+	"cmake":            "🔨",
+	"coconut":          "🥥",
+	"code-climate":     "🗻",
+	"codecov":          "📊",
+	"codeowners":       "👥",
+	"coffee":           "☕",
+	"command":          "💲",
+	"commitlint":       "📏",
+	"conduct":          "🤝",
+	"console":          "💻",
+	"contributing":     "🤝",
+	"cpp":              "🔵",
+	"credits":          "🏅",
+	"csharp":           "🎼",
+	"css":              "🎨",
+	"css-map":          "🧭",
+	"d":                "🔴",
+	"dart":             "🎯",
+	"database":         "💾",
+	"debian":           "🌀",
+	"denizenscript":    "📜",
+	"dhall":            "📐",
+	"disc":             "💿",
+	"django":           "🎸",
+	"docker":           "🐳",
+	"document":         "📄",
+	"dune":             "🐫",
+	"edge":             "🌐",
+	"editorconfig":     "🔧",
+	"ejs":              "📜",
+	"elixir":           "💧",
+	"elm":              "🌳",
+	"email":            "📧",
+	"erlang":           "📞",
+	"eslint":           "📏",
+	"exe":              "🚀",
+	"fastlane":         "🚀",
+	"favicon":          "⭐",
+	"fedora":           "🎩",
+	"firebase":         "🔥",
+	"flash":            "⚡",
+	"font":             "🔤",
+	"fortran":          "📟",
+	"freebsd":          "😈",
+	"fsharp":           "🔷",
+	"gatsby":           "🟣",
+	"gcp":              "🟦",
+	"gemfile":          "💎",
+	"gentoo":           "🐧",
+	"gnu":              "🐃",
+	"git":              "🔀",
+	"github-actions":   "🎬",
+	"gitlab":           "🦊",
+	"go":               "🐹",
+	"go-mod":           "🐹",
+	"go-test":          "🧪",
+	"godot":            "🎮",
+	"godot-assets":     "🎮",
+	"gradle":           "🐘",
+	"graphql":          "🔻",
+	"groovy":           "⭐",
+	"grunt":            "🐗",
+	"gulp":             "🥤",
+	"h":                "📑",
+	"handlebars":       "👨",
+	"haskell":          "🟪",
+	"haxe":             "🔶",
+	"helm":             "⛵",
+	"heroku":           "🟣",
+	"hpp":              "📑",
+	"html":             "🌐",
+	"http":             "🌐",
+	"husky":            "🐶",
+	"i18n":             "🌍",
+	"image":            "🌄",
+	"ionic":            "⚡",
+	"java":             "☕",
+	"javascript":       "🟨",
+	"javascript-map":   "🧭",
+	"jenkins":          "🤵",
+	"jest":             "🃏",
+	"jinja":            "🏯",
+	"json":             "🔣",
+	"julia":            "🟣",
+	"karma":            "🧿",
+	"key":              "🔑",
+	"kotlin":           "🟪",
+	"kubernetes":       "⛵",
+	"laravel":          "🔺",
+	"less":             "🎨",
+	"lib":              "📚",
+	"linux":            "🐧",
+	"liquid":           "💧",
+	"lock":             "🔒",
+	"log":              "📋",
+	"lua":              "🌙",
+	"makefile":         "🔨",
+	"manjaro":          "🐧",
+	"markdown":         "📝",
+	"markojs":          "📜",
+	"mdx":              "📝",
+	"merlin":           "🧙",
+	"midi":             "🎹",
+	"mint":             "🌿",
+	"mjml":             "📧",
+	"mocha":            "☕",
+	"modernizr":        "🟥",
+	"moonscript":       "🌙",
+	"mxml":             "📰",
+	"mysql":            "🐬",
+	"nim":              "👑",
+	"nix":              "⛄",
+	"nodejs":           "🟩",
+	"npm":              "📦",
+	"nuget":            "📦",
+	"nuxt":             "🟩",
+	"ocaml":            "🐫",
+	"opam":             "🐫",
+	"opensuse":         "🦎",
+	"pascal":           "📐",
+	"pawn":             "🐾",
+	"pdf":              "📕",
+	"perl":             "🐪",
+	"php":              "🐘",
+	"postcss":          "🎨",
+	"postgresql":       "🐘",
+	"powerpoint":       "📙",
+	"powershell":       "💻",
+	"prettier":         "💅",
+	"prisma":           "🔺",
+	"prolog":           "🦉",
+	"protractor":       "🔺",
+	"pug":              "🐶",
+	"puppet":           "🎎",
+	"purescript":       "🟫",
+	"python":           "🐍",
+	"python-misc":      "🐍",
+	"qsharp":           "🔮",
+	"r":                "📈",
+	"raml":             "🐏",
+	"raspberry-pi":     "🍓",
+	"razor":            "🪒",
+	"react":            "💠",
+	"react_ts":         "💠",
+	"readme":           "📖",
+	"redhat":           "🎩",
+	"roadmap":          "🧭",
+	"robot":            "🤖",
+	"rollup":           "🍣",
+	"routing":          "🔀",
+	"ruby":             "💎",
+	"rust":             "🦀",
+	"sass":             "🎨",
+	"scheme":           "🔵",
+	"semantic-release": "🔖",
+	"settings":         "🔧",
+	"shaderlab":        "🎨",
+	"sketch":           "💎",
+	"slim":             "📜",
+	"smarty":           "📜",
+	"solidity":         "🔶",
+	"sqlite":           "💾",
+	"storybook":        "📓",
+	"stryker":          "👾",
+	"stylelint":        "📏",
+	"stylus":           "🎨",
+	"sublime":          "📝",
+	"svelte":           "🔥",
+	"svg":              "🎨",
+	"swc":              "🦀",
+	"swift":            "🐦",
+	"table":            "📊",
+	"tailwindcss":      "🌊",
+	"tcl":              "🪶",
+	"terraform":        "🧱",
+	"test-js":          "🧪",
+	"test-jsx":         "🧪",
+	"test-ts":          "🧪",
+	"tex":              "📜",
+	"todo":             "✅",
+	"travis":           "👷",
+	"tune":             "🎧",
+	"twig":             "🌿",
+	"typescript":       "🟦",
+	"typescript-def":   "🟦",
+	"typst":            "📜",
+	"ubuntu":           "🟠",
+	"url":              "🔗",
+	"vagrant":          "📦",
+	"vala":             "📜",
+	"vercel":           "🔺",
+	"verilog":          "🔌",
+	"video":            "🎬",
+	"vim":              "📝",
+	"virtual":          "🥽",
+	"visualstudio":     "🟪",
+	"vscode":           "🟦",
+	"vue":              "🟩",
+	"vue-config":       "🟩",
+	"webpack":          "📦",
+	"word":             "📘",
+	"xaml":             "📰",
+	"xml":              "📰",
+	"yaml":             "📃",
+	"yang":             "🌗",
+	"yarn":             "🧶",
+	"zig":              "⚡",
+	"zip":              "📦",
+	"dir-config":       "🔧",
+	"dir-controller":   "🎮",
+	"dir-download":     "📥",
+	"dir-environment":  "🌱",
+	"dir-git":          "🔀",
+	"dir-github":       "🐙",
+	"dir-images":       "🌄",
+	"dir-import":       "📥",
+	"dir-include":      "📎",
+	"dir-npm":          "📦",
+	"dir-secure":       "🔐",
+	"dir-upload":       "📤",
+
+	// IconDef
+	"dir":        "📁",
+	"diropen":    "📂",
+	"file":       "📄",
+	"hiddendir":  "📁",
+	"hiddenfile": "📄",
+
+	"blockdevice": "💽",
+	"chardevice":  "📟",
+	"pipe":        "🚰",
+	"socket":      "🔌",
+	"setuid":      "🔑",
+	"setgid":      "👥",
+	"brokenlink":  "💔",
+	"mount":       "💾",
+}
@@ -5,7 +5,7 @@ package icons
 
 var IconSet = map[string]*IconInfo{
 	"3d":              {Glyph: "\U0000e79b", Color: [3]uint8{40, 182, 246}},  // 3d
-	"actionscript":    {Glyph: "\U0000fb25", Color: [3]uint8{244, 68, 62}},   // actionscript (Not supported by nerdFont)
+	"actionscript":    {Glyph: "\U000f0241", Color: [3]uint8{244, 68, 62}},   // actionscript
 	"alpine":          {Glyph: "\U0000f300", Color: [3]uint8{14, 87, 123}},   // alpine
 	"android":         {Glyph: "\U000f0032", Color: [3]uint8{139, 195, 74}},  // android
 	"apiblueprint":    {Glyph: "\U0000f031", Color: [3]uint8{66, 165, 245}},  // apiblueprint (Not supported by nerdFont)
@@ -17,7 +17,7 @@ var IconSet = map[string]*IconInfo{
 	"audio":           {Glyph: "\U000f0388", Color: [3]uint8{239, 83, 80}},   // audio
 	"authors":         {Glyph: "\U0000f0c0", Color: [3]uint8{244, 68, 62}},   // authors
 	"autohotkey":      {Glyph: "\U000f0313", Color: [3]uint8{76, 175, 80}},   // autohotkey (Not supported by nerdFont)
-	"azure":           {Glyph: "\U0000fd03", Color: [3]uint8{31, 136, 229}},  // azure
+	"azure":           {Glyph: "\U000f0805", Color: [3]uint8{31, 136, 229}},  // azure
 	"azure-pipelines": {Glyph: "\U0000f427", Color: [3]uint8{20, 101, 192}},  // azure-pipelines
 	"babel":           {Glyph: "\U000f0a25", Color: [3]uint8{253, 217, 59}},  // babel
 	"bitbucket":       {Glyph: "\U0000f171", Color: [3]uint8{31, 136, 229}},  // bitbucket
@@ -81,6 +81,7 @@ var IconSet = map[string]*IconInfo{
 	"gentoo":          {Glyph: "\U0000f30d", Color: [3]uint8{148, 141, 211}}, // gentoo
 	"gnu":             {Glyph: "\U0000e779", Color: [3]uint8{229, 61, 58}},   // GNU
 	"git":             {Glyph: "\U0000e702", Color: [3]uint8{229, 77, 58}},   // git
+	"github-actions":  {Glyph: "\U0000f408", Color: [3]uint8{32, 136, 255}},  // github-actions
 	"gitlab":          {Glyph: "\U0000f296", Color: [3]uint8{226, 69, 57}},   // gitlab
 	"go":              {Glyph: "\U000f07d3", Color: [3]uint8{32, 173, 194}},  // go
 	"go-mod":          {Glyph: "\U000f07d3", Color: [3]uint8{237, 80, 122}},  // go-mod
@@ -116,9 +117,10 @@ var IconSet = map[string]*IconInfo{
 	"karma":           {Glyph: "\U0000e622", Color: [3]uint8{60, 190, 174}},  // karma
 	"key":             {Glyph: "\U000f0306", Color: [3]uint8{48, 166, 154}},  // key
 	"kotlin":          {Glyph: "\U0000e634", Color: [3]uint8{139, 195, 74}},  // kotlin
+	"kubernetes":      {Glyph: "\U000f10fe", Color: [3]uint8{50, 108, 229}},  // kubernetes
 	"laravel":         {Glyph: "\U0000e73f", Color: [3]uint8{248, 80, 81}},   // laravel
 	"less":            {Glyph: "\U0000e60b", Color: [3]uint8{2, 119, 189}},   // less
-	"lib":             {Glyph: "\U0000f831", Color: [3]uint8{139, 195, 74}},  // lib
+	"lib":             {Glyph: "\U000f0331", Color: [3]uint8{139, 195, 74}},  // lib
 	"linux":           {Glyph: "\U0000e712", Color: [3]uint8{238, 207, 55}},  // linux
 	"liquid":          {Glyph: "\U0000e275", Color: [3]uint8{40, 182, 246}},  // liquid
 	"lock":            {Glyph: "\U000f033e", Color: [3]uint8{255, 213, 79}},  // lock
@@ -128,7 +130,7 @@ var IconSet = map[string]*IconInfo{
 	"manjaro":         {Glyph: "\U0000f312", Color: [3]uint8{73, 185, 90}},   // manjaro
 	"markdown":        {Glyph: "\U0000eb1d", Color: [3]uint8{66, 165, 245}},  // markdown
 	"markojs":         {Glyph: "\U0000f13b", Color: [3]uint8{2, 119, 189}},   // markojs (Not supported by nerdFont)
-	"mdx":             {Glyph: "\U0000f853", Color: [3]uint8{255, 202, 61}},  // mdx
+	"mdx":             {Glyph: "\U000f0354", Color: [3]uint8{255, 202, 61}},  // mdx
 	"merlin":          {Glyph: "\U0000f136", Color: [3]uint8{66, 165, 245}},  // merlin (Not supported by nerdFont)
 	"midi":            {Glyph: "\U000f08f2", Color: [3]uint8{66, 165, 245}},  // midi
 	"mint":            {Glyph: "\U0000f30f", Color: [3]uint8{125, 190, 58}},  // mint
@@ -136,7 +138,7 @@ var IconSet = map[string]*IconInfo{
 	"mocha":           {Glyph: "\U000f01aa", Color: [3]uint8{161, 136, 127}}, // mocha (Not supported by nerdFont)
 	"modernizr":       {Glyph: "\U0000e720", Color: [3]uint8{234, 72, 99}},   // modernizr
 	"moonscript":      {Glyph: "\U0000f186", Color: [3]uint8{251, 193, 60}},  // moonscript
-	"mxml":            {Glyph: "\U0000f72d", Color: [3]uint8{254, 168, 62}},  // mxml
+	"mxml":            {Glyph: "\U000f05c0", Color: [3]uint8{254, 168, 62}},  // mxml
 	"mysql":           {Glyph: "\U0000e704", Color: [3]uint8{1, 94, 134}},    // mysql
 	"nim":             {Glyph: "\U000f01a5", Color: [3]uint8{255, 202, 61}},  // nim
 	"nix":             {Glyph: "\U0000f313", Color: [3]uint8{80, 117, 193}},  // nix
@@ -223,7 +225,7 @@ var IconSet = map[string]*IconInfo{
 	"verilog":          {Glyph: "\U000f061a", Color: [3]uint8{250, 111, 66}},  // verilog
 	"video":            {Glyph: "\U000f022b", Color: [3]uint8{253, 154, 62}},  // video
 	"vim":              {Glyph: "\U0000e62b", Color: [3]uint8{67, 160, 71}},   // vim
-	"virtual":          {Glyph: "\U0000f822", Color: [3]uint8{3, 155, 229}},   // virtual
+	"virtual":          {Glyph: "\U000f0894", Color: [3]uint8{3, 155, 229}},   // virtual
 	"visualstudio":     {Glyph: "\U0000e70c", Color: [3]uint8{173, 99, 188}},  // visualstudio
 	"vscode":           {Glyph: "\U0000e70c", Color: [3]uint8{33, 150, 243}},  // vscode (Not supported by nerdFont)
 	"vue":              {Glyph: "\U000f0844", Color: [3]uint8{65, 184, 131}},  // vue
@@ -340,4 +342,14 @@ var IconDef = map[string]*IconInfo{
 	"file":       {Glyph: "\U000f0224", Color: [3]uint8{65, 129, 190}},
 	"hiddendir":  {Glyph: "\U000f0256", Color: [3]uint8{224, 177, 77}},
 	"hiddenfile": {Glyph: "\U000f0613", Color: [3]uint8{65, 129, 190}},
+
+	// special files, drawn by type or mode whatever their name
+	"blockdevice": {Glyph: "\U000f02ca", Color: [3]uint8{253, 216, 53}}, // md-harddisk
+	"chardevice":  {Glyph: "\U000f065c", Color: [3]uint8{253, 216, 53}}, // md-serial_port
+	"pipe":        {Glyph: "\U000f07e5", Color: [3]uint8{192, 202, 51}}, // md-pipe
+	"socket":      {Glyph: "\U000f0318", Color: [3]uint8{171, 71, 188}}, // md-lan_connect
+	"setuid":      {Glyph: "\U000f0888", Color: [3]uint8{229, 57, 53}},  // md-shield_account
+	"setgid":      {Glyph: "\U000f0849", Color: [3]uint8{255, 167, 38}}, // md-account_group
+	"brokenlink":  {Glyph: "\U000f0338", Color: [3]uint8{229, 57, 53}},  // md-link_off
+	"mount":       {Glyph: "\U000f048b", Color: [3]uint8{96, 125, 139}}, // md-server
 }
@@ -0,0 +1,46 @@
+package icons
+
+// CodepointRange is an inclusive range of codepoints.
+type CodepointRange struct {
+	First, Last rune
+	Name        string
+}
+
+// NerdFontRanges lists the codepoints patched into Nerd Fonts v3, by icon
+// set. A glyph in a private use area outside these ranges renders as tofu.
+var NerdFontRanges = []CodepointRange{
+	{0x23fb, 0x23fe, "IEC Power Symbols"},
+	{0x2665, 0x2665, "Octicons"},
+	{0x26a1, 0x26a1, "Octicons"},
+	{0x2b58, 0x2b58, "IEC Power Symbols"},
+	{0xe000, 0xe00a, "Pomicons"},
+	{0xe0a0, 0xe0a3, "Powerline"},
+	{0xe0b0, 0xe0d7, "Powerline Extra"},
+	{0xe200, 0xe2a9, "Font Awesome Extension"},
+	{0xe300, 0xe3e3, "Weather Icons"},
+	{0xe5fa, 0xe6b7, "Seti-UI + Custom"},
+	{0xe700, 0xe8ef, "Devicons"},
+	{0xea60, 0xec1e, "Codicons"},
+	{0xed00, 0xf2ff, "Font Awesome"},
+	{0xf300, 0xf381, "Font Logos"},
+	{0xf400, 0xf533, "Octicons"},
+	{0xf0001, 0xf1af0, "Material Design"},
+}
+
+// NerdFontRemoved is the Material Design range of Nerd Fonts v2, dropped in
+// v3 in favour of the one in plane 15.
+var NerdFontRemoved = CodepointRange{0xf500, 0xfd46, "Material Design (v2)"}
+
+// nerdFontMDIOffset moves a v2 Material Design codepoint to v3. v2 packed
+// the MDI icons from U+F001 on into U+F500 onwards, v3 puts them at their
+// MDI 5 codepoints, U+F0001 onwards, and MDI never renumbers an icon.
+const nerdFontMDIOffset = 0xf0001 - 0xf500
+
+// NerdFontMigration returns the v3 codepoint of r, a codepoint removed in
+// Nerd Fonts v3, and false when r has none.
+func NerdFontMigration(r rune) (rune, bool) {
+	if r < NerdFontRemoved.First || r > NerdFontRemoved.Last {
+		return 0, false
+	}
+	return r + nerdFontMDIOffset, true
+}
@@ -0,0 +1,14 @@
+package icons
+
+// IconPath holds the built-in path rules, tried in order before the name
+// lookups: a file whose ancestors include Dir and whose name matches Glob
+// gets Icon.
+var IconPath = []PathRule{
+	{Dir: ".github/workflows", Glob: "*.yml", Icon: IconSet["github-actions"]},
+	{Dir: ".github/workflows", Glob: "*.yaml", Icon: IconSet["github-actions"]},
+	{Dir: "migrations", Glob: "*.sql", Icon: IconSet["database"]},
+	{Dir: "k8s", Glob: "*.yml", Icon: IconSet["kubernetes"]},
+	{Dir: "k8s", Glob: "*.yaml", Icon: IconSet["kubernetes"]},
+	{Dir: "kubernetes", Glob: "*.yml", Icon: IconSet["kubernetes"]},
+	{Dir: "kubernetes", Glob: "*.yaml", Icon: IconSet["kubernetes"]},
+}
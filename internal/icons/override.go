@@ -4,155 +4,206 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"maps"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
-// Override holds user-supplied icon overrides loaded from a YAML file.
-// A nil *Override is valid and corresponds to "no overrides".
+// Override holds user-supplied icon overrides loaded from one or more YAML
+// files. A nil *Override is valid and corresponds to "no overrides".
 type Override struct {
-	Source string // absolute path of the YAML file
+	sources []string // YAML files layered into this override, bottom first
 
 	dirs      map[string]overrideEntry
 	fileNames map[string]overrideEntry
 	subExts   map[string]overrideEntry
 	exts      map[string]overrideEntry
+	// patterns are tried in order, topmost layer first.
+	patterns []patternRule
+}
+
+// Provenance records where an override entry was defined.
+type Provenance struct {
+	File    string // path of the YAML file
+	Section string // "directories", "files", "patterns", "sub_extensions" or "extensions"
+	Key     string // the entry's key, or the rule's glob or regex, as written in the file
+}
+
+func (p Provenance) String() string {
+	return fmt.Sprintf("%s (%s: %s)", p.File, p.Section, p.Key)
 }
 
 // overrideEntry is the parsed form of a single YAML entry.
 type overrideEntry struct {
-	glyph    string
-	hasColor bool
-	color    [3]uint8
+	glyph string
+	// dark and light are the colors for each background, nil when the
+	// entry keeps the base icon's.
+	dark, light *Color
+	// unset removes the mapping: lookups skip the key in both the
+	// overrides layered below and the built-in tables.
+	unset bool
+	from  Provenance
 }
 
-type yamlEntry struct {
-	Glyph     string `yaml:"glyph"`
-	Codepoint string `yaml:"codepoint"`
-	Color     string `yaml:"color"`
-}
+const (
+	systemConfigDir  = "/etc/logo-ls"
+	overrideDirName  = "logo-ls"
+	overrideFileName = "logo-ls-overrides.yaml"
+	homeOverrideName = ".logo-ls-overrides.yaml"
+)
 
-type yamlFile struct {
-	Directories   map[string]yamlEntry `yaml:"directories"`
-	Files         map[string]yamlEntry `yaml:"files"`
-	Extensions    map[string]yamlEntry `yaml:"extensions"`
-	SubExtensions map[string]yamlEntry `yaml:"sub_extensions"`
+// ConfigDir returns the logo-ls directory under $XDG_CONFIG_HOME (or
+// ~/.config), which holds the override file and the user config. Returns ""
+// when neither can be determined.
+func ConfigDir() string {
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		return filepath.Join(xdg, overrideDirName)
+	}
+	if home, _ := os.UserHomeDir(); home != "" {
+		return filepath.Join(home, ".config", overrideDirName)
+	}
+	return ""
 }
 
-// candidatePaths returns the YAML file locations checked at startup, in order.
+// candidatePaths returns the default override files, lowest precedence
+// first.
 func candidatePaths() []string {
 	var out []string
-	home, _ := os.UserHomeDir()
-	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
-		out = append(out, filepath.Join(xdg, "logo-ls", "logo-ls-overrides.yaml"))
-	} else if home != "" {
-		out = append(out, filepath.Join(home, ".config", "logo-ls", "logo-ls-overrides.yaml"))
+	if runtime.GOOS != "windows" {
+		out = append(out, filepath.Join(systemConfigDir, overrideFileName))
 	}
-	if home != "" {
-		out = append(out, filepath.Join(home, ".logo-ls-overrides.yaml"))
+	if dir := ConfigDir(); dir != "" {
+		out = append(out, filepath.Join(dir, overrideFileName))
+	}
+	if home, _ := os.UserHomeDir(); home != "" {
+		out = append(out, filepath.Join(home, homeOverrideName))
 	}
 	return out
 }
 
-// LoadOverrides discovers and parses the user's override file. Returns
-// (nil, nil) when no file is found or when the file is empty.
-func LoadOverrides() (*Override, error) {
+// CandidatePathDocs describes the locations candidatePaths checks, in the
+// same order, with environment variables left unexpanded. Used to generate
+// documentation.
+func CandidatePathDocs() []string {
+	return []string{
+		systemConfigDir + "/" + overrideFileName,
+		"$XDG_CONFIG_HOME/" + overrideDirName + "/" + overrideFileName,
+		"~/" + homeOverrideName,
+	}
+}
+
+// LoadOverrides reads every default override file that exists, followed by
+// the extra files, and layers them in that order so later files win. A file
+// that can't be read or parsed is left out and invalid entries are skipped;
+// the returned error joins every such problem while the override holds the
+// rest. Returns a nil override when no file defines an entry.
+func LoadOverrides(extra ...string) (*Override, error) {
+	var ov *Override
+	var errs []error
 	for _, path := range candidatePaths() {
-		data, err := os.ReadFile(path)
+		layer, err := LoadOverridesFromPath(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
 		if err != nil {
-			if errors.Is(err, fs.ErrNotExist) {
-				continue
-			}
-			return nil, fmt.Errorf("read %s: %w", path, err)
+			errs = append(errs, err)
+		}
+		ov = ov.Layer(layer)
+	}
+	for _, path := range extra {
+		layer, err := LoadOverridesFromPath(path)
+		if err != nil {
+			errs = append(errs, err)
 		}
-		return parseOverrides(path, data)
+		ov = ov.Layer(layer)
 	}
-	return nil, nil
+	return ov, errors.Join(errs...)
 }
 
-// LoadOverridesFromPath reads and parses an explicit file path, bypassing
-// the default discovery locations.
+// LoadOverridesFromPath reads and parses a single override file.
 func LoadOverridesFromPath(path string) (*Override, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("read %s: %w", path, err)
 	}
-	return parseOverrides(path, data)
+	return ParseOverrides(path, data)
 }
 
-func parseOverrides(path string, data []byte) (*Override, error) {
+// ParseOverrides parses the contents of an override file; path is recorded
+// as the source of its entries and used in error messages. known lists
+// further top-level keys the file may hold, so the sections can be embedded
+// in a larger file; any other key is reported as an unknown section.
+//
+// Invalid entries are reported and skipped while the valid ones are kept:
+// the returned error joins one *Problem per issue, alongside the override.
+// A file that is not valid YAML returns a nil override. Returns (nil, nil)
+// when no entry is defined.
+func ParseOverrides(path string, data []byte, known ...string) (*Override, error) {
 	if len(strings.TrimSpace(string(data))) == 0 {
 		return nil, nil
 	}
-	var raw yamlFile
-	if err := yaml.Unmarshal(data, &raw); err != nil {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
 		return nil, fmt.Errorf("parse %s: %w", path, err)
 	}
-	ov := &Override{Source: path}
-	var err error
-	if ov.dirs, err = buildEntryMap(raw.Directories); err != nil {
-		return nil, fmt.Errorf("%s directories: %w", path, err)
-	}
-	if ov.fileNames, err = buildEntryMap(raw.Files); err != nil {
-		return nil, fmt.Errorf("%s files: %w", path, err)
-	}
-	if ov.exts, err = buildEntryMap(raw.Extensions); err != nil {
-		return nil, fmt.Errorf("%s extensions: %w", path, err)
-	}
-	if ov.subExts, err = buildEntryMap(raw.SubExtensions); err != nil {
-		return nil, fmt.Errorf("%s sub_extensions: %w", path, err)
+	p := &overrideParser{file: path, seen: map[string]*yaml.Node{}}
+	ov := &Override{sources: []string{path}}
+	if len(doc.Content) > 0 {
+		p.root(ov, doc.Content[0], known)
 	}
 	if ov.empty() {
-		return nil, nil
+		return nil, p.err()
 	}
-	return ov, nil
+	return ov, p.err()
 }
 
 func (o *Override) empty() bool {
-	return len(o.dirs)+len(o.fileNames)+len(o.subExts)+len(o.exts) == 0
+	return len(o.dirs)+len(o.fileNames)+len(o.subExts)+len(o.exts)+len(o.patterns) == 0
 }
 
-func buildEntryMap(in map[string]yamlEntry) (map[string]overrideEntry, error) {
-	if len(in) == 0 {
-		return nil, nil
-	}
-	out := make(map[string]overrideEntry, len(in))
-	for key, entry := range in {
-		e, err := parseEntry(entry)
-		if err != nil {
-			return nil, fmt.Errorf("%q: %w", key, err)
-		}
-		out[strings.ToLower(strings.TrimPrefix(key, "."))] = e
-		out[strings.ToLower(key)] = e
+// Sources returns the files layered into o, lowest precedence first.
+func (o *Override) Sources() []string {
+	if o == nil {
+		return nil
 	}
-	return out, nil
+	return o.sources
 }
 
-func parseEntry(e yamlEntry) (overrideEntry, error) {
-	out := overrideEntry{}
-	if e.Glyph != "" || e.Codepoint != "" {
-		g, err := parseGlyph(e.Glyph, e.Codepoint)
-		if err != nil {
-			return out, err
-		}
-		out.glyph = g
-	}
-	if e.Color != "" {
-		c, err := parseColor(e.Color)
-		if err != nil {
-			return out, err
-		}
-		out.hasColor = true
-		out.color = c
+// The YAML sections, also used as Provenance.Section.
+const (
+	sectionDirs    = "directories"
+	sectionFiles   = "files"
+	sectionSubExts = "sub_extensions"
+	sectionExts    = "extensions"
+)
+
+// entries returns the map for section; nil for a nil override.
+func (o *Override) entries(section string) map[string]overrideEntry {
+	if o == nil {
+		return nil
 	}
-	if out.glyph == "" && !out.hasColor {
-		return out, errors.New("override must set at least one of glyph, codepoint, or color")
+	switch section {
+	case sectionDirs:
+		return o.dirs
+	case sectionFiles:
+		return o.fileNames
+	case sectionSubExts:
+		return o.subExts
+	default:
+		return o.exts
 	}
-	return out, nil
+}
+
+// removed reports whether an unset entry drops key from section.
+func (o *Override) removed(section, key string) bool {
+	e, ok := o.entries(section)[key]
+	return ok && e.unset
 }
 
 func parseGlyph(literal, codepoint string) (string, error) {
@@ -192,66 +243,86 @@ func parseCodepoint(s string) (string, error) {
 	return string(rune(n)), nil
 }
 
-func parseColor(s string) ([3]uint8, error) {
-	var out [3]uint8
-	s = strings.TrimSpace(s)
-	s = strings.TrimPrefix(s, "#")
-	switch len(s) {
-	case 3:
-		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
-	case 6:
-	default:
-		return out, fmt.Errorf("invalid color %q: expected #RGB or #RRGGBB", s)
+// Layer returns an Override holding o's entries with top's layered over
+// them: where both define the same key, top's entry replaces o's, so an
+// unset entry in top removes o's mapping too. top's patterns are tried
+// before o's. Either may be nil; neither is
+// modified.
+func (o *Override) Layer(top *Override) *Override {
+	switch {
+	case top == nil:
+		return o
+	case o == nil:
+		return top
 	}
-	n, err := strconv.ParseUint(s, 16, 32)
-	if err != nil {
-		return out, fmt.Errorf("invalid color %q: %w", s, err)
+	return &Override{
+		sources:   append(slices.Clip(o.sources), top.sources...),
+		dirs:      mergeEntries(o.dirs, top.dirs),
+		fileNames: mergeEntries(o.fileNames, top.fileNames),
+		subExts:   mergeEntries(o.subExts, top.subExts),
+		exts:      mergeEntries(o.exts, top.exts),
+		patterns:  append(slices.Clip(top.patterns), o.patterns...),
 	}
-	out[0] = uint8(n >> 16)
-	out[1] = uint8(n >> 8)
-	out[2] = uint8(n)
-	return out, nil
+}
+
+func mergeEntries(base, top map[string]overrideEntry) map[string]overrideEntry {
+	if len(top) == 0 {
+		return base
+	}
+	out := make(map[string]overrideEntry, len(base)+len(top))
+	maps.Copy(out, base)
+	maps.Copy(out, top)
+	return out
+}
+
+// Provenance returns where the override entry applied to the given entry
+// of directory dir was defined. ok is false when the built-in icon is used unchanged.
+func (o *Override) Provenance(dir, name, fileExt, indicator string) (p Provenance, ok bool) {
+	e, ok := o.lookupEntry(dir, name, fileExt, indicator)
+	return e.from, ok
 }
 
 // lookupEntry returns the first matching override entry across the lookup
-func (o *Override) lookupEntry(name, fileExt, indicator string) (overrideEntry, bool) {
+// chain, trying the patterns right after the exact file name. Unset entries
+// are skipped so the next step of the chain applies.
+func (o *Override) lookupEntry(dir, name, fileExt, indicator string) (overrideEntry, bool) {
 	if o == nil {
 		return overrideEntry{}, false
 	}
-	lowerNameExt := strings.ToLower(name + fileExt)
-	if indicator == "/" {
-		e, ok := o.dirs[lowerNameExt]
-		return e, ok
-	}
-	if e, ok := o.fileNames[lowerNameExt]; ok {
-		return e, true
-	}
-	t := strings.Split(name, ".")
-	if len(t) > 1 && t[0] != "" {
-		subKey := strings.ToLower(t[len(t)-1] + fileExt)
-		if e, ok := o.subExts[subKey]; ok {
+	for _, k := range lookupKeys(name, fileExt, indicator) {
+		if e, ok := o.entries(k.section)[k.key]; ok && !e.unset {
 			return e, true
 		}
-	}
-	extKey := strings.ToLower(strings.TrimPrefix(fileExt, "."))
-	if e, ok := o.exts[extKey]; ok {
-		return e, true
+		if k.section == sectionFiles {
+			if e, ok := o.matchPattern(dir, name+fileExt); ok {
+				return e, true
+			}
+		}
 	}
 	return overrideEntry{}, false
 }
 
 // apply returns a copy of base with the entry's set fields swapped in.
 func (e overrideEntry) apply(base *IconInfo) *IconInfo {
-	if base == nil {
-		out := &IconInfo{Glyph: e.glyph, Color: e.color}
-		return out
+	var out IconInfo
+	if base != nil {
+		out = *base
 	}
-	out := *base
 	if e.glyph != "" {
-		out.Glyph = e.glyph
+		out.Glyph, out.Name = e.glyph, ""
 	}
-	if e.hasColor {
-		out.Color = e.color
+	if e.dark != nil || e.light != nil {
+		paint := out.paint()
+		if e.dark != nil {
+			paint.Dark = *e.dark
+		}
+		if e.light != nil {
+			paint.Light = *e.light
+		}
+		out.Color, out.Paint = paint.Dark.RGB, nil
+		if paint.Dark != paint.Light || paint.Dark.Palette {
+			out.Paint = &paint
+		}
 	}
 	return &out
 }
@@ -1,8 +1,11 @@
 package icons_test
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/canta2899/logo-ls/internal/icons"
@@ -254,7 +257,7 @@ func TestLoadOverridesDiscovery(t *testing.T) {
 	if err != nil {
 		t.Fatalf("LoadOverrides: %v", err)
 	}
-	if ov == nil || ov.Source != path {
+	if ov == nil || !slices.Equal(ov.Sources(), []string{path}) {
 		t.Fatalf("expected override loaded from %s, got %+v", path, ov)
 	}
 }
@@ -277,7 +280,7 @@ func TestLoadOverridesDiscoveryXDG(t *testing.T) {
 	if err != nil {
 		t.Fatalf("LoadOverrides: %v", err)
 	}
-	if ov == nil || ov.Source != path {
+	if ov == nil || !slices.Equal(ov.Sources(), []string{path}) {
 		t.Fatalf("expected override loaded from XDG path %s, got %+v", path, ov)
 	}
 }
@@ -293,3 +296,304 @@ func TestLoadOverridesMissingFile(t *testing.T) {
 		t.Fatalf("expected nil override, got %+v", ov)
 	}
 }
+
+func TestLayerAndProvenance(t *testing.T) {
+	user, err := icons.ParseOverrides("user.yaml", []byte("extensions:\n  rs:\n    glyph: \"U+E7A8\"\n  go:\n    glyph: \"X\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	project, err := icons.ParseOverrides("project.yaml", []byte("extensions:\n  go:\n    glyph: \"Y\"\nsort: time\n"), "sort")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ov := user.Layer(project)
+	if got := icons.ResolveWith(ov, "main", ".go", "").GetGlyph(); got != "Y" {
+		t.Errorf("expected the top layer to win, got %q", got)
+	}
+	if got := icons.ResolveWith(ov, "lib", ".rs", "").GetGlyph(); got != "" {
+		t.Errorf("expected the bottom layer to show through, got %q", got)
+	}
+	want := icons.Provenance{File: "project.yaml", Section: "extensions", Key: "go"}
+	if got, ok := ov.Provenance("", "main", ".go", ""); !ok || got != want {
+		t.Errorf("expected .go from %v, got %v", want, got)
+	}
+	if got, _ := ov.Provenance("", "lib", ".rs", ""); got.File != "user.yaml" {
+		t.Errorf("expected .rs from user.yaml, got %v", got)
+	}
+	if got, ok := ov.Provenance("", "README", ".md", ""); ok {
+		t.Errorf("expected no provenance for a built-in icon, got %v", got)
+	}
+	if got := want.String(); got != "project.yaml (extensions: go)" {
+		t.Errorf("unexpected Provenance.String %q", got)
+	}
+	if got := ov.Sources(); !slices.Equal(got, []string{"user.yaml", "project.yaml"}) {
+		t.Errorf("expected both sources in order, got %q", got)
+	}
+	// layering leaves the inputs alone
+	if got := icons.ResolveWith(user, "main", ".go", "").GetGlyph(); got != "X" {
+		t.Errorf("expected the user layer unchanged, got %q", got)
+	}
+
+	var none *icons.Override
+	if none.Layer(project) != project || project.Layer(nil) != project {
+		t.Error("expected layering with nil to return the other override")
+	}
+}
+
+// writeOverride writes data to name in dir and returns its path.
+func writeOverride(t *testing.T, dir, name, data string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Verifies every default file and each extra file are merged entry by
+// entry, later files winning.
+func TestLoadOverridesMergesSources(t *testing.T) {
+	home, xdg := t.TempDir(), t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+
+	xdgPath := writeOverride(t, xdg, "logo-ls/logo-ls-overrides.yaml", "extensions:\n  rs: {glyph: A}\n  go: {glyph: A}\n  ts: {glyph: A}\n")
+	homePath := writeOverride(t, home, ".logo-ls-overrides.yaml", "extensions:\n  go: {glyph: B}\n  ts: {glyph: B}\n")
+	extra := writeOverride(t, t.TempDir(), "extra.yaml", "extensions:\n  ts: {color: \"#010203\"}\n")
+
+	ov, err := icons.LoadOverrides(extra)
+	if err != nil {
+		t.Fatalf("LoadOverrides: %v", err)
+	}
+	if got := ov.Sources(); !slices.Equal(got[len(got)-3:], []string{xdgPath, homePath, extra}) {
+		t.Errorf("expected the sources in precedence order, got %q", got)
+	}
+	for ext, glyph := range map[string]string{".rs": "A", ".go": "B"} {
+		if got := icons.ResolveWith(ov, "main", ext, "").GetGlyph(); got != glyph {
+			t.Errorf("%s: expected glyph %q, got %q", ext, glyph, got)
+		}
+	}
+	// the extra file replaces the home entry as a whole
+	got := icons.ResolveWith(ov, "main", ".ts", "")
+	if got.GetGlyph() != icons.Resolve("main", ".ts", "").GetGlyph() || got.Color != [3]uint8{1, 2, 3} {
+		t.Errorf("expected the built-in .ts glyph with the extra color, got %q %v", got.GetGlyph(), got.Color)
+	}
+	if p, _ := ov.Provenance("", "main", ".ts", ""); p.File != extra {
+		t.Errorf("expected .ts from %s, got %v", extra, p)
+	}
+}
+
+// Verifies a broken file is reported while the others still apply, and a
+// missing --override-file is an error.
+func TestLoadOverridesKeepsGoodSources(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	writeOverride(t, home, ".logo-ls-overrides.yaml", "extensions:\n  rs: {glyph: A}\n")
+	bad := writeOverride(t, t.TempDir(), "bad.yaml", "extensions:\n  go: {color: nope}\n")
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+
+	ov, err := icons.LoadOverrides(bad, missing)
+	if err == nil || !strings.Contains(err.Error(), bad) || !strings.Contains(err.Error(), missing) {
+		t.Errorf("expected errors naming %s and %s, got %v", bad, missing, err)
+	}
+	if got := icons.ResolveWith(ov, "main", ".rs", "").GetGlyph(); got != "A" {
+		t.Errorf("expected the good file to apply, got %q", got)
+	}
+}
+
+// Verifies unset removes a built-in mapping and a mapping from a lower
+// layer, falling through to the next step of the lookup chain.
+func TestOverrideUnset(t *testing.T) {
+	ov, err := loadYAML(t, "files:\n  go.mod: {unset: true}\nextensions:\n  go: {unset: true}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	def := icons.Resolve("main", ".nothing", "")
+	if got := icons.ResolveWith(ov, "main", ".go", ""); got.GetGlyph() != def.GetGlyph() {
+		t.Errorf("expected the default file icon for an unset extension, got %q", got.GetGlyph())
+	}
+	// go.mod falls through to the extension table
+	if got, want := icons.ResolveWith(ov, "go", ".mod", ""), icons.Resolve("x", ".mod", ""); got.GetGlyph() != want.GetGlyph() {
+		t.Errorf("expected go.mod to fall back to the .mod icon %q, got %q", want.GetGlyph(), got.GetGlyph())
+	}
+	if _, ok := ov.Provenance("", "main", ".go", ""); ok {
+		t.Error("expected no provenance for an unset entry")
+	}
+
+	user, err := icons.ParseOverrides("user.yaml", []byte("extensions:\n  rs: {glyph: X}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	project, err := icons.ParseOverrides("project.yaml", []byte("extensions:\n  rs: {unset: true}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := icons.ResolveWith(user.Layer(project), "lib", ".rs", "").GetGlyph(); got != def.GetGlyph() {
+		t.Errorf("expected unset to remove both the user and built-in .rs icons, got %q", got)
+	}
+
+	if _, err := loadYAML(t, "extensions:\n  rs: {unset: true, glyph: X}\n"); err == nil {
+		t.Error("expected an error when unset is combined with a glyph")
+	}
+}
+
+// Verifies pattern rules apply in order, after exact file names and before
+// sub-extensions, and that layered files put their rules first.
+func TestOverridePatterns(t *testing.T) {
+	ov, err := loadYAML(t, `
+files:
+  app.test.ts: {glyph: F}
+patterns:
+  - glob: "*.TEST.ts"
+    glyph: G
+  - regex: '^Dockerfile\..+$'
+    glyph: R
+  - glob: "*.ts"
+    glyph: L
+sub_extensions:
+  test.ts: {glyph: S}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct{ name, ext, want string }{
+		{"app.test", ".ts", "F"},    // exact file name first
+		{"lib.test", ".ts", "G"},    // globs ignore case
+		{"main", ".ts", "L"},        // later rule
+		{"Dockerfile", ".dev", "R"}, // regex
+		{"dockerfile", ".dev", ""},  // regexes match as written
+	}
+	for _, c := range cases {
+		got := icons.ResolveWith(ov, c.name, c.ext, "").GetGlyph()
+		if c.want == "" {
+			c.want = icons.Resolve(c.name, c.ext, "").GetGlyph()
+		}
+		if got != c.want {
+			t.Errorf("%s%s: expected %q, got %q", c.name, c.ext, c.want, got)
+		}
+	}
+	if got := icons.ResolveWith(ov, "src", ".ts", "/").GetGlyph(); got == "L" {
+		t.Error("expected patterns not to apply to directories")
+	}
+	want := icons.Provenance{File: ov.Sources()[0], Section: "patterns", Key: "*.TEST.ts"}
+	if got, _ := ov.Provenance("", "lib.test", ".ts", ""); got != want {
+		t.Errorf("expected provenance %v, got %v", want, got)
+	}
+
+	top, err := icons.ParseOverrides("top.yaml", []byte("patterns:\n  - glob: \"*.ts\"\n    glyph: T\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := icons.ResolveWith(ov.Layer(top), "lib.test", ".ts", "").GetGlyph(); got != "T" {
+		t.Errorf("expected the top layer's rules first, got %q", got)
+	}
+}
+
+// Verifies invalid rules are reported with their line.
+func TestOverridePatternErrors(t *testing.T) {
+	cases := []struct{ yaml, msg string }{
+		{"patterns:\n  - glob: \"*.go\"\n    glyph: G\n  - regex: \"(\"\n    glyph: R\n", ":4:12: patterns: invalid regex \"(\""},
+		{"patterns:\n  - glob: \"[\"\n    glyph: G\n", ":2:11: patterns: invalid glob \"[\""},
+		{"patterns:\n  - glyph: G\n", ":2:5: patterns: a rule must set glob or regex"},
+		{"patterns:\n  - glob: a\n    regex: b\n    glyph: G\n", ":2:5: patterns: a rule takes either glob or regex, not both"},
+		{"patterns:\n  - glob: a\n    unset: true\n", ":3:12: patterns: \"a\": unset is only valid for exact keys"},
+		{"patterns:\n  - glob: a\n", ":2:5: patterns: \"a\": override must set"},
+		{"patterns:\n  glob: a\n", ":2:3: patterns: expected a list of rules"},
+	}
+	for _, c := range cases {
+		_, err := loadYAML(t, c.yaml)
+		if err == nil || !strings.Contains(err.Error(), c.msg) {
+			t.Errorf("%q: expected an error containing %q, got %v", c.yaml, c.msg, err)
+		}
+	}
+}
+
+// Verifies built-in and override rules that match on ancestor directories.
+func TestResolveInPathRules(t *testing.T) {
+	actions := icons.IconSet["github-actions"].GetGlyph()
+	cases := []struct {
+		dir, name, ext string
+		want           string
+	}{
+		{"/src/repo/.github/workflows", "ci", ".yml", actions},
+		{`C:\repo\.GitHub\Workflows`, "ci", ".yaml", actions},
+		{"/src/repo/.github", "ci", ".yml", icons.Resolve("ci", ".yml", "").GetGlyph()},
+		{"/src/repo/workflows", "ci", ".yml", icons.Resolve("ci", ".yml", "").GetGlyph()},
+		{"/src/deploy/k8s/base", "app", ".yaml", icons.IconSet["kubernetes"].GetGlyph()},
+	}
+	for _, c := range cases {
+		if got := icons.ResolveIn(nil, c.dir, c.name, c.ext, "").GetGlyph(); got != c.want {
+			t.Errorf("%s/%s%s: expected %q, got %q", c.dir, c.name, c.ext, c.want, got)
+		}
+	}
+	if got := icons.ResolveIn(nil, "/repo/.github/workflows", "ci.yml", "", "/").GetGlyph(); got == actions {
+		t.Error("expected path rules not to apply to directories")
+	}
+
+	ov, err := loadYAML(t, "patterns:\n  - dir: db/migrations\n    glob: \"*.sql\"\n    glyph: M\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := icons.ResolveIn(ov, "/app/db/migrations/2024", "001_init", ".sql", "").GetGlyph(); got != "M" {
+		t.Errorf("expected the override rule below db/migrations, got %q", got)
+	}
+	if got := icons.ResolveIn(ov, "/app/migrations", "001_init", ".sql", "").GetGlyph(); got == "M" {
+		t.Error("expected the override rule to need both directory names")
+	}
+	if got, _ := ov.Provenance("/app/db/migrations", "x", ".sql", ""); got.Key != "db/migrations/*.sql" {
+		t.Errorf("expected the rule's directory in the provenance key, got %q", got.Key)
+	}
+}
+
+// Verifies problems carry their position, unknown and duplicate keys are
+// reported as ignored, invalid entries as skipped, and valid entries
+// survive invalid ones.
+func TestParseOverridesProblems(t *testing.T) {
+	ov, err := icons.ParseOverrides("icons.yaml", []byte(`extensions:
+  go: {color: nope}
+  rs: {glyph: R, colour: "#fff"}
+  .RS: {glyph: X}
+  py: {glyph: P}
+fils:
+  x: {glyph: Y}
+hide: ["*.gen.go"]
+`), "hide")
+	var got []string
+	var skipped []bool
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var problem *icons.Problem
+		if !errors.As(e, &problem) {
+			t.Fatalf("expected a *Problem, got %v", e)
+		}
+		got = append(got, e.Error())
+		skipped = append(skipped, problem.Skipped)
+	}
+	want := []string{
+		`icons.yaml:2:15: extensions: "go": invalid color "nope": expected #RGB, #RRGGBB, rgb(), hsl(), a CSS color name, xterm:N, ansi:NAME or @icon`,
+		`icons.yaml:3:18: extensions: "rs": unknown key "colour"`,
+		`icons.yaml:4:3: extensions: duplicate key ".RS" (first at line 3)`,
+		`icons.yaml:6:1: unknown section "fils"`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+	// only the invalid color drops its entry
+	if want := []bool{true, false, false, false}; !slices.Equal(skipped, want) {
+		t.Errorf("expected Skipped %v, got %v", want, skipped)
+	}
+	// the later duplicate wins; the invalid entry is skipped
+	if g := icons.ResolveWith(ov, "main", ".rs", "").GetGlyph(); g != "X" {
+		t.Errorf("expected the later duplicate to win, got %q", g)
+	}
+	if g := icons.ResolveWith(ov, "main", ".py", "").GetGlyph(); g != "P" {
+		t.Errorf("expected the valid entry to apply, got %q", g)
+	}
+	if g, want := icons.ResolveWith(ov, "main", ".go", "").GetGlyph(), icons.Resolve("main", ".go", "").GetGlyph(); g != want {
+		t.Errorf("expected the invalid entry to be skipped, got %q", g)
+	}
+}
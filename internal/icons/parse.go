@@ -0,0 +1,249 @@
+package icons
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Problem is one issue found in an override file: an invalid entry, which
+// is skipped, or an unknown or duplicate key, which is ignored.
+type Problem struct {
+	File         string
+	Line, Column int
+	Msg          string
+	// Skipped is set when the entry, or the section, was left out; the
+	// other problems are ignored keys and sections around entries kept.
+	Skipped bool
+}
+
+func (p *Problem) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", p.File, p.Line, p.Column, p.Msg)
+}
+
+// entryKeys are the keys of an icon entry.
+var entryKeys = []string{"glyph", "codepoint", "color", "unset"}
+
+// overrideParser walks the YAML nodes of one override file, collecting
+// problems as it goes so one bad entry does not discard the others.
+type overrideParser struct {
+	file     string
+	problems []error
+	// seen holds the key node of every entry by section and lookup key,
+	// to report duplicates.
+	seen map[string]*yaml.Node
+}
+
+// report records a problem that is ignored: the entry around it is kept.
+func (p *overrideParser) report(n *yaml.Node, format string, args ...any) {
+	p.problems = append(p.problems, &Problem{File: p.file, Line: n.Line, Column: n.Column, Msg: fmt.Sprintf(format, args...)})
+}
+
+// skip records a problem that leaves the entry at n out.
+func (p *overrideParser) skip(n *yaml.Node, format string, args ...any) {
+	p.problems = append(p.problems, &Problem{File: p.file, Line: n.Line, Column: n.Column, Msg: fmt.Sprintf(format, args...), Skipped: true})
+}
+
+func (p *overrideParser) err() error {
+	return errors.Join(p.problems...)
+}
+
+// root reads the top-level sections into ov.
+func (p *overrideParser) root(ov *Override, node *yaml.Node, known []string) {
+	if isNull(node) {
+		return
+	}
+	if node.Kind != yaml.MappingNode {
+		p.skip(node, "expected a mapping of sections")
+		return
+	}
+	sections := map[string]*yaml.Node{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], deref(node.Content[i+1])
+		if first, dup := sections[key.Value]; dup {
+			p.report(key, "duplicate section %q (first at line %d)", key.Value, first.Line)
+		}
+		sections[key.Value] = key
+		switch key.Value {
+		case sectionDirs:
+			ov.dirs = p.entryMap(sectionDirs, value, ov.dirs)
+		case sectionFiles:
+			ov.fileNames = p.entryMap(sectionFiles, value, ov.fileNames)
+		case sectionSubExts:
+			ov.subExts = p.entryMap(sectionSubExts, value, ov.subExts)
+		case sectionExts:
+			ov.exts = p.entryMap(sectionExts, value, ov.exts)
+		case sectionPatterns:
+			ov.patterns = append(ov.patterns, p.patterns(value)...)
+		default:
+			if !slices.Contains(known, key.Value) {
+				p.report(key, "unknown section %q", key.Value)
+			}
+		}
+	}
+}
+
+// entryMap adds the entries of a section to into. A key repeated with a
+// different case or leading dot is reported; the later entry wins.
+func (p *overrideParser) entryMap(section string, node *yaml.Node, into map[string]overrideEntry) map[string]overrideEntry {
+	if isNull(node) {
+		return into
+	}
+	if node.Kind != yaml.MappingNode {
+		p.skip(node, "%s: expected a mapping of names to icons", section)
+		return into
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], deref(node.Content[i+1])
+		lookup := lookupKeyOf(section, key.Value)
+		if first, dup := p.seen[section+"\x00"+lookup]; dup {
+			p.report(key, "%s: duplicate key %q (first at line %d)", section, key.Value, first.Line)
+		}
+		p.seen[section+"\x00"+lookup] = key
+		prefix := fmt.Sprintf("%s: %q", section, key.Value)
+		fields, ok := p.fields(prefix, value, entryKeys)
+		if !ok {
+			continue
+		}
+		e, ok := p.entry(prefix, value, fields)
+		if !ok {
+			continue
+		}
+		e.from = Provenance{File: p.file, Section: section, Key: key.Value}
+		if into == nil {
+			into = map[string]overrideEntry{}
+		}
+		into[strings.ToLower(strings.TrimPrefix(key.Value, "."))] = e
+		into[strings.ToLower(key.Value)] = e
+	}
+	return into
+}
+
+// fields returns the values of a mapping by key. Keys not in allowed are
+// reported and ignored, as are repeated ones after the first. ok is false
+// when node is not a mapping of scalar keys.
+func (p *overrideParser) fields(prefix string, node *yaml.Node, allowed []string) (map[string]*yaml.Node, bool) {
+	if node.Kind != yaml.MappingNode {
+		p.skip(node, "%s: expected a mapping with %s", prefix, strings.Join(allowed, ", "))
+		return nil, false
+	}
+	out := map[string]*yaml.Node{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], deref(node.Content[i+1])
+		switch {
+		case !slices.Contains(allowed, key.Value):
+			p.report(key, "%s: unknown key %q", prefix, key.Value)
+		case out[key.Value] != nil:
+			p.report(key, "%s: duplicate key %q (first at line %d)", prefix, key.Value, out[key.Value].Line)
+		default:
+			out[key.Value] = value
+		}
+	}
+	return out, true
+}
+
+// entry parses the icon fields of an entry or pattern rule.
+func (p *overrideParser) entry(prefix string, node *yaml.Node, fields map[string]*yaml.Node) (overrideEntry, bool) {
+	var out overrideEntry
+	for _, key := range []string{"glyph", "codepoint"} {
+		if n := fields[key]; n != nil && n.Kind != yaml.ScalarNode {
+			p.skip(n, "%s: %s: expected a string", prefix, key)
+			return out, false
+		}
+	}
+	if n := fields["unset"]; n != nil {
+		if err := n.Decode(&out.unset); err != nil {
+			p.skip(n, "%s: unset: expected true or false", prefix)
+			return out, false
+		}
+	}
+	if out.unset {
+		if fields["glyph"] != nil || fields["codepoint"] != nil || fields["color"] != nil {
+			p.skip(node, "%s: unset cannot be combined with glyph, codepoint, or color", prefix)
+			return out, false
+		}
+		return out, true
+	}
+	var (
+		err error
+		ok  bool
+	)
+	switch glyph, codepoint := fields["glyph"], fields["codepoint"]; {
+	case codepoint != nil:
+		if out.glyph, err = parseCodepoint(codepoint.Value); err != nil {
+			p.skip(codepoint, "%s: %v", prefix, err)
+			return out, false
+		}
+	case glyph != nil:
+		if out.glyph, err = parseGlyph(glyph.Value, ""); err != nil {
+			p.skip(glyph, "%s: %v", prefix, err)
+			return out, false
+		}
+	}
+	if color := fields["color"]; color != nil {
+		if out.dark, out.light, ok = p.colors(prefix, color); !ok {
+			return out, false
+		}
+	}
+	if out.glyph == "" && out.dark == nil && out.light == nil {
+		p.skip(node, "%s: override must set at least one of glyph, codepoint, color, or unset", prefix)
+		return out, false
+	}
+	return out, true
+}
+
+// colors parses an entry's color: a single color used on both backgrounds,
+// or a mapping setting dark and light separately.
+func (p *overrideParser) colors(prefix string, node *yaml.Node) (dark, light *Color, ok bool) {
+	if node.Kind == yaml.ScalarNode {
+		c, err := parseColor(node.Value)
+		if err != nil {
+			p.skip(node, "%s: %v", prefix, err)
+			return nil, nil, false
+		}
+		return &c, &c, true
+	}
+	prefix += ": color"
+	fields, ok := p.fields(prefix, node, []string{"dark", "light"})
+	if !ok {
+		return nil, nil, false
+	}
+	out := map[string]*Color{}
+	for _, key := range []string{"dark", "light"} {
+		n := fields[key]
+		if n == nil {
+			continue
+		}
+		if n.Kind != yaml.ScalarNode {
+			p.skip(n, "%s: %s: expected a string", prefix, key)
+			return nil, nil, false
+		}
+		c, err := parseColor(n.Value)
+		if err != nil {
+			p.skip(n, "%s: %s: %v", prefix, key, err)
+			return nil, nil, false
+		}
+		out[key] = &c
+	}
+	if len(out) == 0 {
+		p.skip(node, "%s: expected dark, light, or both", prefix)
+		return nil, nil, false
+	}
+	return out["dark"], out["light"], true
+}
+
+// isNull reports whether a section was left empty.
+func isNull(n *yaml.Node) bool {
+	return n == nil || n.Kind == 0 || n.Tag == "!!null"
+}
+
+// deref follows an alias to the node it refers to.
+func deref(n *yaml.Node) *yaml.Node {
+	if n.Kind == yaml.AliasNode && n.Alias != nil {
+		return n.Alias
+	}
+	return n
+}
@@ -0,0 +1,47 @@
+package icons
+
+import (
+	"path"
+	"strings"
+)
+
+// PathRule gives files below a directory their own icon, e.g. YAML files
+// in .github/workflows.
+type PathRule struct {
+	// Dir is one or more slash-separated directory names that must appear,
+	// consecutively, among the entry's ancestors; case is ignored.
+	Dir  string
+	Glob string // path.Match pattern for the file name, lower case
+	Icon *IconInfo
+}
+
+func (r PathRule) match(dir, name string) bool {
+	if !underDir(splitDir(dir), splitDir(r.Dir)) {
+		return false
+	}
+	ok, _ := path.Match(r.Glob, strings.ToLower(name))
+	return ok
+}
+
+// splitDir splits a slash or backslash separated path into its names.
+func splitDir(dir string) []string {
+	return strings.FieldsFunc(dir, func(r rune) bool { return r == '/' || r == '\\' })
+}
+
+// underDir reports whether want appears as a consecutive run of names in
+// ancestors. An empty want matches anything.
+func underDir(ancestors, want []string) bool {
+	for i := 0; i+len(want) <= len(ancestors); i++ {
+		matched := true
+		for j, name := range want {
+			if !strings.EqualFold(ancestors[i+j], name) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package icons
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// sectionPatterns is the YAML section holding the ordered pattern rules.
+const sectionPatterns = "patterns"
+
+// patternRule is one entry of the patterns section: a glob or a regular
+// expression matched against the whole file name, optionally restricted to
+// files below a directory, and the icon to apply.
+//
+//	patterns:
+//	  - glob: "*.test.ts"
+//	    glyph: "U+F0668"
+//	  - regex: '^Dockerfile\..+$'
+//	    color: "#2496ed"
+//	  - dir: db/migrations
+//	    glob: "*.sql"
+//	    glyph: "U+E706"
+//
+// Globs use path.Match syntax and ignore case like the exact file names;
+// regular expressions are RE2 and match as written, so use (?i) for
+// case-insensitive ones. dir matches like PathRule.Dir.
+type patternRule struct {
+	dir   []string       // ancestor names the entry must be below
+	glob  string         // lower-cased glob, when the rule is a glob
+	regex *regexp.Regexp // when the rule is a regular expression
+	entry overrideEntry
+}
+
+func (r patternRule) match(dir, name string) bool {
+	if len(r.dir) > 0 && !underDir(splitDir(dir), r.dir) {
+		return false
+	}
+	if r.regex != nil {
+		return r.regex.MatchString(name)
+	}
+	ok, _ := path.Match(r.glob, strings.ToLower(name))
+	return ok
+}
+
+// patternKeys are the keys of a pattern rule.
+var patternKeys = append([]string{"dir", "glob", "regex"}, entryKeys...)
+
+// patterns compiles the patterns section, skipping invalid rules.
+func (p *overrideParser) patterns(node *yaml.Node) []patternRule {
+	if isNull(node) {
+		return nil
+	}
+	if node.Kind != yaml.SequenceNode {
+		p.skip(node, "patterns: expected a list of rules")
+		return nil
+	}
+	rules := make([]patternRule, 0, len(node.Content))
+	for _, item := range node.Content {
+		if rule, ok := p.pattern(deref(item)); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func (p *overrideParser) pattern(node *yaml.Node) (patternRule, bool) {
+	var rule patternRule
+	fields, ok := p.fields("patterns", node, patternKeys)
+	if !ok {
+		return rule, false
+	}
+	for _, key := range []string{"dir", "glob", "regex"} {
+		if n := fields[key]; n != nil && n.Kind != yaml.ScalarNode {
+			p.skip(n, "patterns: %s: expected a string", key)
+			return rule, false
+		}
+	}
+	dir, glob, regex := fields["dir"], fields["glob"], fields["regex"]
+	var key string
+	switch {
+	case glob != nil && regex != nil:
+		p.skip(node, "patterns: a rule takes either glob or regex, not both")
+		return rule, false
+	case glob != nil:
+		key = glob.Value
+		rule.glob = strings.ToLower(glob.Value)
+		if _, err := path.Match(rule.glob, ""); err != nil {
+			p.skip(glob, "patterns: invalid glob %q: %v", glob.Value, err)
+			return rule, false
+		}
+	case regex != nil:
+		key = regex.Value
+		re, err := regexp.Compile(regex.Value)
+		if err != nil {
+			p.skip(regex, "patterns: invalid regex %q: %v", regex.Value, err)
+			return rule, false
+		}
+		rule.regex = re
+	default:
+		p.skip(node, "patterns: a rule must set glob or regex")
+		return rule, false
+	}
+	if dir != nil && dir.Value != "" {
+		rule.dir = splitDir(dir.Value)
+		key = dir.Value + "/" + key
+	}
+	prefix := fmt.Sprintf("patterns: %q", key)
+	if unset := fields["unset"]; unset != nil {
+		p.skip(unset, "%s: unset is only valid for exact keys", prefix)
+		return rule, false
+	}
+	entry, ok := p.entry(prefix, node, fields)
+	if !ok {
+		return rule, false
+	}
+	entry.from = Provenance{File: p.file, Section: sectionPatterns, Key: key}
+	rule.entry = entry
+	return rule, true
+}
+
+// matchPattern returns the entry of the first rule matching name in dir.
+func (o *Override) matchPattern(dir, name string) (overrideEntry, bool) {
+	for _, r := range o.patterns {
+		if r.match(dir, name) {
+			return r.entry, true
+		}
+	}
+	return overrideEntry{}, false
+}
@@ -10,45 +10,105 @@ func Resolve(name, ext, indicator string) *IconInfo {
 // ResolveWith is like Resolve but applies a user-provided Override on top of
 // the built-in lookup.
 func ResolveWith(ov *Override, name, fileExt, indicator string) *IconInfo {
-	base := resolveBuiltin(name, fileExt, indicator)
-	if entry, ok := ov.lookupEntry(name, fileExt, indicator); ok {
+	return ResolveIn(ov, "", name, fileExt, indicator)
+}
+
+// ResolveIn is like ResolveWith for an entry of directory dir, so that path
+// rules matching on its ancestors apply too.
+func ResolveIn(ov *Override, dir, name, fileExt, indicator string) *IconInfo {
+	base := resolveBuiltin(ov, dir, name, fileExt, indicator)
+	if entry, ok := ov.lookupEntry(dir, name, fileExt, indicator); ok {
 		base = entry.apply(base)
 	}
 	return applyIndicator(base, indicator)
 }
 
-// resolveBuiltin runs the built-in lookup chain without any override
-func resolveBuiltin(name, fileExt, indicator string) *IconInfo {
-	lowerNameExt := strings.ToLower(name + fileExt)
+// lookupKey is one step of the lookup chain: a key into one section.
+type lookupKey struct {
+	section, key string
+}
 
+// lookupKeys returns the lookup chain for an entry, most specific first:
+// the directory name for directories, otherwise the file name, its
+// sub-extension and its extension.
+func lookupKeys(name, fileExt, indicator string) []lookupKey {
+	lowerNameExt := strings.ToLower(name + fileExt)
 	if indicator == "/" {
-		if i, ok := IconDir[lowerNameExt]; ok {
-			return i
-		}
-		if len(name) == 0 || name[0] == '.' {
-			return IconDef["hiddendir"]
-		}
-		return IconDef["dir"]
-	}
-
-	if i, ok := IconFileName[lowerNameExt]; ok {
-		return i
+		return []lookupKey{{sectionDirs, lowerNameExt}}
 	}
+	keys := []lookupKey{{sectionFiles, lowerNameExt}}
 	t := strings.Split(name, ".")
 	if len(t) > 1 && t[0] != "" {
-		subKey := strings.ToLower(t[len(t)-1] + fileExt)
-		if i, ok := IconSubExt[subKey]; ok {
-			return i
+		keys = append(keys, lookupKey{sectionSubExts, strings.ToLower(t[len(t)-1] + fileExt)})
+	}
+	return append(keys, lookupKey{sectionExts, strings.ToLower(strings.TrimPrefix(fileExt, "."))})
+}
+
+// builtinTables maps each section to its built-in table.
+var builtinTables = map[string]map[string]*IconInfo{
+	sectionDirs:    IconDir,
+	sectionFiles:   IconFileName,
+	sectionSubExts: IconSubExt,
+	sectionExts:    IconExt,
+}
+
+// resolveBuiltin runs the built-in path rules and lookup chain, skipping
+// the keys ov unsets, and falls back to the default icons.
+func resolveBuiltin(ov *Override, dir, name, fileExt, indicator string) *IconInfo {
+	return matchBuiltin(ov, dir, name, fileExt, indicator).Icon
+}
+
+// matchBuiltin is resolveBuiltin returning the mapping that matched.
+func matchBuiltin(ov *Override, dir, name, fileExt, indicator string) Mapping {
+	if indicator != "/" && dir != "" {
+		for _, r := range IconPath {
+			if r.match(dir, name+fileExt) {
+				return Mapping{Section: sectionPaths, Key: r.Dir + "/" + r.Glob, Icon: r.Icon}
+			}
+		}
+	}
+	for _, k := range lookupKeys(name, fileExt, indicator) {
+		if ov.removed(k.section, k.key) {
+			continue
+		}
+		if i, ok := builtinTables[k.section][k.key]; ok {
+			return Mapping{Section: k.section, Key: k.key, Icon: i}
 		}
 	}
-	extKey := strings.ToLower(strings.TrimPrefix(fileExt, "."))
-	if i, ok := IconExt[extKey]; ok {
-		return i
+	hidden := len(name) == 0 || name[0] == '.'
+	key := "file"
+	switch {
+	case indicator == "/" && hidden:
+		key = "hiddendir"
+	case indicator == "/":
+		key = "dir"
+	case hidden:
+		key = "hiddenfile"
 	}
-	if len(name) == 0 || name[0] == '.' {
-		return IconDef["hiddenfile"]
+	return Mapping{Section: sectionDefault, Key: key, Icon: IconDef[key]}
+}
+
+// IsFallback reports whether i is one of the default file icons used when
+// nothing matches an entry's name.
+func IsFallback(i *IconInfo) bool {
+	for _, key := range []string{"file", "hiddenfile", "exe"} {
+		def := IconDef[key]
+		if i.GetGlyph() == def.Glyph && i.Color == def.Color {
+			return true
+		}
+	}
+	return false
+}
+
+// ForKind returns the IconSet entry named kind for an entry with the given
+// indicator, or nil when there is no such entry. Used for icons picked from
+// a file's contents rather than its name.
+func ForKind(kind, indicator string) *IconInfo {
+	i, ok := IconSet[kind]
+	if !ok {
+		return nil
 	}
-	return IconDef["file"]
+	return applyIndicator(i, indicator)
 }
 
 // applyIndicator handles the "*" executable special case and the nil fallback.
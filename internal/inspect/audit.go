@@ -0,0 +1,111 @@
+package inspect
+
+import (
+	iofs "io/fs"
+	"strings"
+
+	"github.com/canta2899/logo-ls/pkg/fs"
+)
+
+// Audit warnings, in the order Audit reports them.
+const (
+	// WarnWorldWritable marks a file anyone may write to.
+	WarnWorldWritable = "world-writable"
+	// WarnNoSticky marks a world-writable directory without the sticky
+	// bit, where anyone may delete or replace the files of others.
+	WarnNoSticky = "no-sticky"
+	WarnSetuid   = "setuid"
+	WarnSetgid   = "setgid"
+	// WarnForeignOwner marks a file in the home directory owned by
+	// another user.
+	WarnForeignOwner = "foreign-owner"
+	// WarnSSHKeyWritable marks an SSH key or authorized_keys file that
+	// its group or others may write to.
+	WarnSSHKeyWritable = "ssh-key-writable"
+	// WarnLinkOutside marks a symlink leading out of the listed tree.
+	WarnLinkOutside = "link-outside"
+)
+
+// AuditWarnings lists every warning Audit reports, in its order.
+var AuditWarnings = []string{
+	WarnWorldWritable, WarnNoSticky, WarnSetuid, WarnSetgid,
+	WarnForeignOwner, WarnSSHKeyWritable, WarnLinkOutside,
+}
+
+// AuditScope is what Audit judges an entry against.
+type AuditScope struct {
+	// Root is the listed directory; symlinks leading out of it are
+	// reported.
+	Root string
+	// Home is the user's home directory and UID their id; files in Home
+	// owned by another id are reported. A negative UID, where ids do not
+	// apply, skips the check.
+	Home string
+	UID  int
+}
+
+// Audit returns the warnings for e, for --audit. It reads the mode, the
+// sticky bit, the owner id and the symlink target, which the inspector
+// gathers in any mode when Options.Audit is set.
+func (i *Inspector) Audit(e *InspectedEntry, scope AuditScope) []string {
+	if e.Mode == 0 {
+		return nil
+	}
+	var warnings []string
+	worldWritable := e.Mode.Perm()&0o002 != 0
+	switch {
+	case e.IsDir():
+		if worldWritable && !e.Sticky && e.Mode&iofs.ModeSticky == 0 {
+			warnings = append(warnings, WarnNoSticky)
+		}
+	case e.IsSymlink():
+		// a link's own permissions mean nothing
+	case worldWritable:
+		warnings = append(warnings, WarnWorldWritable)
+	}
+	if e.Mode&iofs.ModeSetuid != 0 {
+		warnings = append(warnings, WarnSetuid)
+	}
+	if e.Mode&iofs.ModeSetgid != 0 && !e.IsDir() {
+		// setgid directories only pass their group on to new files
+		warnings = append(warnings, WarnSetgid)
+	}
+	if scope.UID >= 0 && scope.Home != "" && e.UID != uint32(scope.UID) && i.within(e.AbsPath, scope.Home) {
+		warnings = append(warnings, WarnForeignOwner)
+	}
+	if e.Kind == KindFile && e.Mode.Perm()&0o022 != 0 && i.isSSHKey(e) {
+		warnings = append(warnings, WarnSSHKeyWritable)
+	}
+	if e.IsSymlink() && scope.Root != "" && !i.within(i.linkDestination(e), scope.Root) {
+		warnings = append(warnings, WarnLinkOutside)
+	}
+	return warnings
+}
+
+// isSSHKey reports whether e is a key or authorized_keys file: anything in
+// a .ssh directory but the known_hosts and config files, or an id_* key
+// elsewhere.
+func (i *Inspector) isSSHKey(e *InspectedEntry) bool {
+	if i.fs.Base(i.fs.Dir(e.AbsPath)) == ".ssh" {
+		return !strings.HasPrefix(e.Name, "known_hosts") && e.Name != "config"
+	}
+	return strings.HasPrefix(e.Name, "id_")
+}
+
+// linkDestination returns where symlink e finally leads, or where its
+// first hop points when it is broken.
+func (i *Inspector) linkDestination(e *InspectedEntry) string {
+	if e.LinkResolved != nil {
+		return e.LinkResolved.AbsPath
+	}
+	if fs.IsAbs(i.fs, e.LinkTarget) {
+		return e.LinkTarget
+	}
+	return i.fs.Join(i.fs.Dir(e.AbsPath), e.LinkTarget)
+}
+
+// within reports whether p is dir or inside it.
+func (i *Inspector) within(p, dir string) bool {
+	p, dir = i.fs.Join(p), strings.TrimSuffix(i.fs.Join(dir), i.fs.Separator())
+	return p == dir || strings.HasPrefix(p, dir+i.fs.Separator())
+}
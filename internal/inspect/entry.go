@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/canta2899/logo-ls/internal/icons"
+	"github.com/canta2899/logo-ls/internal/inspect/platform"
 )
 
 // Kind classifies a filesystem entry.
@@ -20,6 +21,8 @@ const (
 	KindSymlink
 	KindPipe
 	KindSocket
+	KindBlockDevice
+	KindCharDevice
 )
 
 // InspectedEntry is the single source of truth for one filesystem entry.
@@ -40,22 +43,56 @@ type InspectedEntry struct {
 	Blocks    int64
 	Owner     string // raw, unpadded
 	Group     string // raw, unpadded
+	Author    string // raw, unpadded; set with Options.ShowAuthor
+	UID       uint32 // owner id; set whenever platform metadata is read
 	HasXAttr  bool
+	HasACL    bool
 	Sticky    bool // S_ISVTX is set
 	StickyX   bool // sticky AND other-executable
 
+	// XAttrs lists the extended attributes when Options.ListXAttr is set.
+	XAttrs []platform.XAttr
+	// Context is the SELinux context when Options.WantContext is set.
+	Context string
+	// Flags are the chattr flags, when Options.WantFlags is set and they
+	// could be read (FlagsKnown); HasCap marks file capabilities.
+	Flags      uint32
+	FlagsKnown bool
+	HasCap     bool
+
+	// Dev is the device the entry is on. MountPoint marks directories on
+	// another device than their parent, when Options.Mounts is set;
+	// FSType is the file system type when Options.FSType is set.
+	Dev        uint64
+	MountPoint bool
+	FSType     string
+
+	// Device files only: the device numbers shown in place of the size.
+	Major, Minor uint32
+
 	// Symlinks only. Populated when Kind == KindSymlink.
-	LinkTarget   string // raw target as stored on disk (empty if EvalSymlinks not called)
+	LinkTarget string // raw target as stored on disk (readlink); empty if not resolved
+	// LinkChain holds the stored targets of the further hops when the
+	// target is itself a symlink.
+	LinkChain []string
+	// LinkResolved is the final target; nil when the link is broken.
 	LinkResolved *InspectedEntry
+	LinkBroken   bool // the target does not exist or cannot be reached
+	LinkLoop     bool // following the link exceeded the hop limit (ELOOP)
 
 	// Derived. Set by the inspector; safe to override in tests.
 	Icon      *icons.IconInfo
 	Indicator string
 	GitStatus string
+	// Warnings are the --audit findings, see Inspector.Audit.
+	Warnings []string
 }
 
 func (e *InspectedEntry) IsDir() bool     { return e.Kind == KindDir }
 func (e *InspectedEntry) IsSymlink() bool { return e.Kind == KindSymlink }
+func (e *InspectedEntry) IsDevice() bool {
+	return e.Kind == KindBlockDevice || e.Kind == KindCharDevice
+}
 
 func kindFromMode(m iofs.FileMode) Kind {
 	switch {
@@ -67,6 +104,10 @@ func kindFromMode(m iofs.FileMode) Kind {
 		return KindPipe
 	case m&iofs.ModeSocket != 0:
 		return KindSocket
+	case m&iofs.ModeCharDevice != 0:
+		return KindCharDevice
+	case m&iofs.ModeDevice != 0:
+		return KindBlockDevice
 	default:
 		return KindFile
 	}
@@ -6,8 +6,8 @@ type defaultIconResolver struct {
 	ov *icons.Override
 }
 
-func (r defaultIconResolver) Resolve(name, ext, indicator string) *icons.IconInfo {
-	return icons.ResolveWith(r.ov, name, ext, indicator)
+func (r defaultIconResolver) Resolve(dir, name, ext, indicator string) *icons.IconInfo {
+	return icons.ResolveIn(r.ov, dir, name, ext, indicator)
 }
 
 // DefaultIconResolver returns the package-level icon resolver with no overrides.
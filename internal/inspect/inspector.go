@@ -1,9 +1,12 @@
 package inspect
 
 import (
+	"errors"
 	iofs "io/fs"
 	"os"
+	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/canta2899/logo-ls/pkg/fs"
 	"github.com/canta2899/logo-ls/internal/icons"
@@ -31,16 +34,29 @@ type Options struct {
 	Long            bool // populate Mode/Owner/Group/HardLinks
 	ShowOwner       bool
 	ShowGroup       bool
+	ShowAuthor      bool
+	NumericIDs      bool // show uid/gid instead of looking up names
 	ShowInode       bool
 	ShowBlocks      bool
 	WantXAttr       bool // call Listxattr; only meaningful in long mode
+	ListXAttr       bool // also read attribute names and values for XAttrs
+	WantContext     bool // read the SELinux context, in any mode
+	WantFlags       bool // read chattr flags and capabilities; long mode only
+	Audit           bool // gather what Audit reads, in any mode
+	Mounts          bool // mark directories that are mount points
+	FSType          bool // look up the file system type of every entry
 	ResolveSymlinks bool // populate LinkResolved (long mode follows targets for icons)
+	LinkChain       bool // show every hop of a symlink to a symlink in long mode
 	DisableIcon     bool
+	// Sniff reads the start of regular files whose name matches no icon,
+	// to pick one from their contents.
+	Sniff bool
 }
 
-// IconResolver picks an icon for a filesystem entry.
+// IconResolver picks an icon for a filesystem entry. dir is the directory
+// holding the entry, so that rules can match on its ancestors.
 type IconResolver interface {
-	Resolve(name, ext, indicator string) *icons.IconInfo
+	Resolve(dir, name, ext, indicator string) *icons.IconInfo
 }
 
 // namedOwner is implemented by FileInfo values that know their owner/group
@@ -58,6 +74,13 @@ type Inspector struct {
 	icons    IconResolver
 	options  Options
 	platform platform.Reader
+
+	// parent and parentDev cache the directory isMountPoint last looked
+	// at, as entries come a directory at a time.
+	parent    string
+	parentDev uint64
+	// fsTypes maps devices to file system types; read on first use.
+	fsTypes map[uint64]string
 }
 
 // New returns a fresh inspector.
@@ -88,11 +111,12 @@ func (i *Inspector) Inspect(absPath string, fi fs.FileInfo) *InspectedEntry {
 	e.ModTime = fi.ModTime()
 	e.Kind = kindFromMode(fi.Mode())
 
-	if i.options.ShowInode || i.options.ShowBlocks || i.options.Long {
+	if i.options.ShowInode || i.options.ShowBlocks || i.options.Long || i.options.WantContext || i.options.Audit ||
+		i.options.FSType || (i.options.Mounts && e.Kind == KindDir) {
 		i.applyPlatformStat(e, absPath, fi)
 	}
 
-	if e.Kind == KindSymlink && (i.options.ResolveSymlinks || i.options.Long) {
+	if e.Kind == KindSymlink && (i.options.ResolveSymlinks || i.options.Long || i.options.Audit) {
 		i.resolveSymlink(e, absPath)
 	}
 
@@ -106,7 +130,24 @@ func (i *Inspector) Inspect(absPath string, fi fs.FileInfo) *InspectedEntry {
 }
 
 func (i *Inspector) applyPlatformStat(e *InspectedEntry, absPath string, fi fs.FileInfo) {
-	stat := i.platform.Read(absPath, fi, platform.Options{WantXAttr: i.options.Long && i.options.WantXAttr})
+	stat := i.platform.Read(absPath, fi, platform.Options{
+		WantXAttr:   i.options.Long && i.options.WantXAttr,
+		ListXAttr:   i.options.Long && i.options.ListXAttr,
+		WantContext: i.options.WantContext,
+		WantFlags:   i.options.Long && i.options.WantFlags,
+	})
+	e.Context = stat.Context
+	e.UID = stat.UID
+	e.Dev = stat.Dev
+	if i.options.Mounts && e.Kind == KindDir {
+		e.MountPoint = i.isMountPoint(absPath, stat.Dev)
+	}
+	if i.options.FSType {
+		e.FSType = i.fsType(stat.Dev)
+	}
+	if i.options.Audit {
+		e.Sticky, e.StickyX = stat.Sticky, stat.StickyX
+	}
 	if i.options.ShowInode {
 		e.Inode = stat.Inode
 	}
@@ -127,68 +168,214 @@ func (i *Inspector) applyLongStat(e *InspectedEntry, fi fs.FileInfo, stat platfo
 		e.HardLinks = 1
 	}
 	e.HasXAttr = stat.HasXAttr
+	e.HasACL = stat.HasACL
+	e.Flags, e.FlagsKnown, e.HasCap = stat.Flags, stat.FlagsKnown, stat.HasCap
+	e.XAttrs = stat.XAttrs
 	e.Sticky = stat.Sticky
 	e.StickyX = stat.StickyX
+	if e.IsDevice() {
+		e.Major, e.Minor = stat.Major, stat.Minor
+	}
 	i.applyOwnerGroup(e, fi, stat)
 }
 
+// applyOwnerGroup names the owner, author and group of e. Ids without a
+// name, and all of them with NumericIDs, are shown as numbers like ls does.
 func (i *Inspector) applyOwnerGroup(e *InspectedEntry, fi fs.FileInfo, stat platform.Stat) {
-	if no, ok := fi.(namedOwner); ok {
+	no, named := fi.(namedOwner)
+	if i.options.ShowOwner || i.options.ShowAuthor {
+		var owner string
+		switch {
+		case i.options.NumericIDs:
+		case named:
+			owner = no.OwnerName()
+		default:
+			owner = i.platform.LookupOwner(stat.UID)
+		}
+		if owner == "" {
+			owner = strconv.FormatUint(uint64(stat.UID), 10)
+		}
 		if i.options.ShowOwner {
-			e.Owner = no.OwnerName()
+			e.Owner = owner
+		}
+		if i.options.ShowAuthor {
+			// only the Hurd records authors; elsewhere it is the owner
+			e.Author = owner
 		}
-		if i.options.ShowGroup {
+	}
+	if i.options.ShowGroup {
+		switch {
+		case i.options.NumericIDs:
+		case named:
 			e.Group = no.GroupName()
+		default:
+			e.Group = i.platform.LookupGroup(stat.GID)
+		}
+		if e.Group == "" {
+			e.Group = strconv.FormatUint(uint64(stat.GID), 10)
 		}
-		return
 	}
-	if i.options.ShowOwner {
-		e.Owner = i.platform.LookupOwner(stat.UID)
+}
+
+// isMountPoint reports whether the directory at absPath, on device dev,
+// is on another device than its parent or is the root of the file system.
+func (i *Inspector) isMountPoint(absPath string, dev uint64) bool {
+	parent := i.fs.Dir(absPath)
+	if parent == absPath {
+		return true
 	}
-	if i.options.ShowGroup {
-		e.Group = i.platform.LookupGroup(stat.GID)
+	if parent != i.parent {
+		pfi, err := i.fs.Stat(parent)
+		if err != nil {
+			return false
+		}
+		i.parent, i.parentDev = parent, i.platform.Read(parent, pfi, platform.Options{}).Dev
 	}
+	return dev != i.parentDev
 }
 
+// fsType returns the type of the file system on device dev, or "" when
+// the mount table cannot be read (on systems other than Linux).
+func (i *Inspector) fsType(dev uint64) string {
+	if i.fsTypes == nil {
+		data, _ := fs.ReadFile(i.fs, platform.MountInfoPath)
+		i.fsTypes = platform.ParseMountInfo(data)
+	}
+	return i.fsTypes[dev]
+}
+
+// maxLinkHops is how many symlinks resolveSymlink follows before reporting
+// a loop, as on Linux.
+const maxLinkHops = 40
+
+// resolveSymlink follows the link at absPath one hop at a time, recording
+// the stored target of each hop, and marks the link broken when a hop is
+// missing or the chain comes back to a link it went through.
 func (i *Inspector) resolveSymlink(e *InspectedEntry, absPath string) {
-	target, err := i.fs.EvalSymlinks(absPath)
+	target, err := fs.Readlink(i.fs, absPath)
 	if err != nil {
 		return
 	}
 	e.LinkTarget = target
-	tfi, terr := i.fs.Stat(target)
-	if terr != nil {
-		return
-	}
-	e.LinkResolved = &InspectedEntry{
-		AbsPath: target,
-		Name:    tfi.Name(),
-		Mode:    tfi.Mode(),
-		Size:    tfi.Size(),
-		ModTime: tfi.ModTime(),
-		Kind:    kindFromMode(tfi.Mode()),
+	cur := absPath
+	visited := map[string]bool{absPath: true}
+	for hops := 1; ; hops++ {
+		if !fs.IsAbs(i.fs, target) {
+			// relative to where the link's directory really is, so a ".."
+			// under a symlinked directory climbs out of its target
+			dir := i.fs.Dir(cur)
+			if real, err := i.fs.EvalSymlinks(dir); err == nil {
+				dir = real
+			}
+			target = i.fs.Join(dir, target)
+		}
+		if hops > maxLinkHops || visited[target] {
+			e.LinkBroken, e.LinkLoop = true, true
+			return
+		}
+		cur = target
+		visited[cur] = true
+		tfi, err := i.fs.Lstat(cur)
+		if err != nil {
+			e.LinkBroken, e.LinkLoop = true, errors.Is(err, syscall.ELOOP)
+			return
+		}
+		if tfi.Mode()&iofs.ModeSymlink == 0 {
+			e.LinkResolved = &InspectedEntry{
+				AbsPath: cur,
+				Name:    tfi.Name(),
+				Mode:    tfi.Mode(),
+				Size:    tfi.Size(),
+				ModTime: tfi.ModTime(),
+				Kind:    kindFromMode(tfi.Mode()),
+			}
+			return
+		}
+		if target, err = fs.Readlink(i.fs, cur); err != nil {
+			e.LinkBroken = true
+			return
+		}
+		e.LinkChain = append(e.LinkChain, target)
 	}
 }
 
 func (i *Inspector) resolveIcon(e *InspectedEntry) *icons.IconInfo {
+	if e.LinkBroken {
+		return icons.IconDef["brokenlink"]
+	}
+	if e.MountPoint {
+		return icons.IconDef["mount"]
+	}
+	target, indicator := e, e.Indicator
 	if e.Kind == KindSymlink && e.LinkResolved != nil {
-		tname, text := splitNameExt(e.LinkResolved.Name, i.fs)
-		return i.icons.Resolve(tname, text, classifyIndicator(e.LinkResolved.Mode))
+		target, indicator = e.LinkResolved, classifyIndicator(e.LinkResolved.Mode)
+	}
+	if key := SpecialIcon(target.Mode); key != "" {
+		return icons.IconDef[key]
 	}
-	name, ext := splitNameExt(e.Name, i.fs)
-	return i.icons.Resolve(name, ext, e.Indicator)
+	name, ext := splitNameExt(target.Name, i.fs)
+	icon := i.icons.Resolve(i.fs.Dir(target.AbsPath), name, ext, indicator)
+	if i.options.Sniff && target.Kind == KindFile && icons.IsFallback(icon) {
+		if sniffed := icons.ForKind(i.sniff(target.AbsPath), indicator); sniffed != nil {
+			return sniffed
+		}
+	}
+	return icon
+}
+
+// SpecialIcon returns the IconDef key of the icon drawn for an entry with
+// mode m whatever its name: devices, pipes and sockets by their kind, and
+// setuid and setgid files, which ls colors apart for security. "" for
+// everything else.
+func SpecialIcon(m iofs.FileMode) string {
+	switch kindFromMode(m) {
+	case KindBlockDevice:
+		return "blockdevice"
+	case KindCharDevice:
+		return "chardevice"
+	case KindPipe:
+		return "pipe"
+	case KindSocket:
+		return "socket"
+	case KindFile:
+		switch {
+		case m&iofs.ModeSetuid != 0:
+			return "setuid"
+		case m&iofs.ModeSetgid != 0:
+			return "setgid"
+		}
+	}
+	return ""
 }
 
 // indicatorFor returns the trailing classifier glyph ("/", "@", "*", ...).
-// For symlinks in long mode it emits " ~> target" with HOME folded to "~".
-// Unresolvable symlinks get an empty indicator in long mode.
+// For symlinks in long mode it emits " ~> target" with HOME folded to "~",
+// with the further hops when LinkChain is set, and marks broken and looping
+// links. Unreadable symlinks get an empty indicator in long mode. In the
+// other modes a broken or looping link gets "@!", so it stands out without
+// colors or icons.
 func (i *Inspector) indicatorFor(e *InspectedEntry, m iofs.FileMode) string {
 	if m&iofs.ModeSymlink != 0 {
 		if i.options.Long {
 			if e.LinkTarget == "" {
 				return ""
 			}
-			return " ~> " + foldHome(e.LinkTarget)
+			s := " ~> " + foldHome(e.LinkTarget)
+			if i.options.LinkChain {
+				for _, hop := range e.LinkChain {
+					s += " ~> " + foldHome(hop)
+				}
+			}
+			switch {
+			case e.LinkLoop:
+				s += " [loop]"
+			case e.LinkBroken:
+				s += " [broken]"
+			}
+			return s
+		}
+		if e.LinkBroken {
+			return "@!"
 		}
 		return "@"
 	}
@@ -1,10 +1,12 @@
 package inspect_test
 
 import (
+	iofs "io/fs"
 	"testing"
 	"time"
 
 	"github.com/canta2899/logo-ls/pkg/fs/fakefs"
+	"github.com/canta2899/logo-ls/internal/icons"
 	"github.com/canta2899/logo-ls/internal/inspect"
 )
 
@@ -106,3 +108,101 @@ func TestInspector_DirIndicator(t *testing.T) {
 		t.Errorf("kind: %v", e.Kind)
 	}
 }
+
+func TestInspector_Sniff(t *testing.T) {
+	meta := fakefs.Meta{Mode: 0o644}
+	file := func(name, data string) *fakefs.Entry {
+		return fakefs.Contents(fakefs.File(name, 0, mtime("2026-01-02 10:00:00"), meta), data)
+	}
+	root := fakefs.Dir("root", fakefs.Meta{Mode: 0o755},
+		file("deploy", "#!/bin/bash\nset -e\n"),
+		file("serve", "#!/usr/bin/env -S node --no-warnings\n"),
+		file("manage", "#!/usr/bin/env python3.12\n"),
+		file("tool", "\x7fELF\x02\x01\x01"),
+		file("logo", "\x89PNG\r\n\x1a\n...."),
+		file("report", "%PDF-1.7\n"),
+		file("backup", "\x1f\x8b\x08\x00"),
+		file("app", "SQLite format 3\x00...."),
+		file("notes", "just text"),
+		file("script.go", "#!/bin/sh\n"),
+		fakefs.Symlink("run", "deploy", meta),
+	)
+	vfs := fakefs.New(root)
+
+	cases := map[string]string{
+		"deploy":    "console",
+		"serve":     "nodejs",
+		"manage":    "python",
+		"tool":      "exe",
+		"logo":      "image",
+		"report":    "pdf",
+		"backup":    "zip",
+		"app":       "sqlite",
+		"run":       "console",
+		"notes":     "",
+		"script.go": "",
+	}
+	for _, sniff := range []bool{false, true} {
+		insp := inspect.New(vfs, inspect.DefaultIconResolver(), inspect.Options{Sniff: sniff, ResolveSymlinks: true})
+		for name, kind := range cases {
+			fi, err := vfs.Lstat("/root/" + name)
+			if err != nil {
+				t.Fatalf("lstat: %v", err)
+			}
+			got := insp.Inspect("/root/"+name, fi).Icon
+			want := icons.Resolve(name, "", "")
+			if name == "script.go" {
+				want = icons.Resolve("script", ".go", "")
+			}
+			if sniff && kind != "" {
+				want = icons.IconSet[kind]
+			}
+			if got.GetGlyph() != want.GetGlyph() {
+				t.Errorf("sniff=%v %s: expected glyph %q, got %q", sniff, name, want.GetGlyph(), got.GetGlyph())
+			}
+		}
+	}
+}
+
+func TestInspector_SpecialFiles(t *testing.T) {
+	meta := fakefs.Meta{Owner: "root", Group: "disk", Mode: 0o660, Inode: "300"}
+	vfs := fakefs.New(fakefs.Dir("dev", fakefs.Meta{Mode: 0o755},
+		fakefs.BlockDevice("sda", 8, 0, meta),
+		fakefs.CharDevice("tty0", 4, 0, meta),
+		fakefs.Pipe("initctl", meta),
+		fakefs.Socket("log", meta),
+		fakefs.File("su.sh", 10, mtime("2026-01-02 10:00:00"), fakefs.Meta{Mode: 0o755 | iofs.ModeSetuid}),
+		fakefs.File("wall.py", 10, mtime("2026-01-02 10:00:00"), fakefs.Meta{Mode: 0o755 | iofs.ModeSetgid}),
+	))
+	insp := inspect.New(vfs, inspect.DefaultIconResolver(), inspect.Options{Long: true})
+	tests := []struct {
+		name         string
+		kind         inspect.Kind
+		major, minor uint32
+		mode         string
+		icon         string
+	}{
+		{"sda", inspect.KindBlockDevice, 8, 0, "brw-rw----", "blockdevice"},
+		{"tty0", inspect.KindCharDevice, 4, 0, "crw-rw----", "chardevice"},
+		{"initctl", inspect.KindPipe, 0, 0, "prw-rw----", "pipe"},
+		{"log", inspect.KindSocket, 0, 0, "srw-rw----", "socket"},
+		{"su.sh", inspect.KindFile, 0, 0, "-rwsr-xr-x", "setuid"},
+		{"wall.py", inspect.KindFile, 0, 0, "-rwxr-sr-x", "setgid"},
+	}
+	for _, tt := range tests {
+		fi, err := vfs.Lstat("/dev/" + tt.name)
+		if err != nil {
+			t.Fatalf("lstat: %v", err)
+		}
+		e := insp.Inspect("/dev/"+tt.name, fi)
+		if e.Kind != tt.kind || e.Major != tt.major || e.Minor != tt.minor {
+			t.Errorf("%s: expected kind %d %d,%d, got %d %d,%d", tt.name, tt.kind, tt.major, tt.minor, e.Kind, e.Major, e.Minor)
+		}
+		if got := inspect.ModeString(e.Mode, e.Sticky, e.StickyX, e.HasXAttr, e.HasACL); got != tt.mode+" " {
+			t.Errorf("%s: expected mode %q, got %q", tt.name, tt.mode, got)
+		}
+		if e.Icon != icons.IconDef[tt.icon] {
+			t.Errorf("%s: expected the %s icon, got %+v", tt.name, tt.icon, e.Icon)
+		}
+	}
+}
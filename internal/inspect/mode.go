@@ -8,35 +8,146 @@ import (
 )
 
 // ModeString builds the long-mode 11-char permission string from raw mode
-// bits, sticky bits and an HasXAttr flag. Returns a placeholder if mode is
-// zero (i.e. the inspector failed to stat).
-func ModeString(mode iofs.FileMode, sticky, stickyX, hasXAttr bool) string {
+// bits, sticky bits and the HasXAttr and HasACL flags, the way ls does: a
+// type letter (b and c for devices), then rwx triplets with s/S for setuid
+// and setgid and t/T for sticky, then + for an ACL or else @ for extended
+// attributes. Returns a placeholder if mode is zero (i.e. the inspector
+// failed to stat).
+func ModeString(mode iofs.FileMode, sticky, stickyX, hasXAttr, hasACL bool) string {
 	if mode == 0 {
 		return fmt.Sprintf("%-*s", 11, strings.Repeat("?", 11))
 	}
-	s := mode.String()
-	// FileMode.String() returns "L..." for symlinks; the conventional ls
-	// rendering is lowercase 'l'.
-	if mode&iofs.ModeSymlink != 0 && len(s) > 0 && s[0] == 'L' {
-		s = "l" + s[1:]
+	b := []byte{typeLetter(mode)}
+	const rwx = "rwxrwxrwx"
+	for i := range 9 {
+		if mode&(1<<(8-i)) != 0 {
+			b = append(b, rwx[i])
+		} else {
+			b = append(b, '-')
+		}
 	}
+	setSpecial(b, 3, mode&iofs.ModeSetuid != 0, 's')
+	setSpecial(b, 6, mode&iofs.ModeSetgid != 0, 's')
 	if sticky {
-		// FileMode.String() encodes ModeSticky as 't' at position 9 for
-		// Unix-style modes; substitute the trailing char to honour stickyX
-		// vs sticky-only.
-		if len(s) >= 10 {
-			if stickyX {
-				s = s[:9] + "t" + s[10:]
-			} else {
-				s = s[:9] + "T" + s[10:]
-			}
+		// the platform layer reports sticky from the raw stat; stickyX
+		// says whether others may execute
+		b[9] = 'T'
+		if stickyX {
+			b[9] = 't'
 		}
+	} else {
+		setSpecial(b, 9, mode&iofs.ModeSticky != 0, 't')
 	}
-	if hasXAttr {
+	s := string(b)
+	switch {
+	case hasACL:
+		s += "+"
+	case hasXAttr:
 		s += "@"
 	}
 	return fmt.Sprintf("%-*s", 11, s)
 }
 
+// typeLetter returns the file type letter opening a mode string.
+func typeLetter(mode iofs.FileMode) byte {
+	switch {
+	case mode&iofs.ModeDir != 0:
+		return 'd'
+	case mode&iofs.ModeSymlink != 0:
+		return 'l'
+	case mode&iofs.ModeNamedPipe != 0:
+		return 'p'
+	case mode&iofs.ModeSocket != 0:
+		return 's'
+	case mode&iofs.ModeCharDevice != 0:
+		return 'c'
+	case mode&iofs.ModeDevice != 0:
+		return 'b'
+	case mode&iofs.ModeIrregular != 0:
+		return '?'
+	}
+	return '-'
+}
+
+// setSpecial replaces the execute letter at b[i] with letter when set, in
+// upper case when the execute bit is off.
+func setSpecial(b []byte, i int, set bool, letter byte) {
+	if !set {
+		return
+	}
+	if b[i] != 'x' {
+		letter -= 'a' - 'A'
+	}
+	b[i] = letter
+}
+
+// OctalMode returns the permission bits as four octal digits, e.g. 0755,
+// the first holding setuid (4), setgid (2) and sticky (1). sticky is the
+// sticky bit as read by the platform layer, which FileMode may lack.
+func OctalMode(mode iofs.FileMode, sticky bool) string {
+	special := 0
+	if mode&iofs.ModeSetuid != 0 {
+		special |= 4
+	}
+	if mode&iofs.ModeSetgid != 0 {
+		special |= 2
+	}
+	if sticky || mode&iofs.ModeSticky != 0 {
+		special |= 1
+	}
+	return fmt.Sprintf("%d%03o", special, mode.Perm())
+}
+
+// inodeFlags are the chattr flags in the order lsattr prints them, with
+// their FS_*_FL bits.
+var inodeFlags = []struct {
+	bit    uint32
+	letter byte
+}{
+	{0x00000001, 's'}, // secure deletion
+	{0x00000002, 'u'}, // undeletable
+	{0x00000008, 'S'}, // synchronous updates
+	{0x00010000, 'D'}, // synchronous directory updates
+	{0x00000010, 'i'}, // immutable
+	{0x00000020, 'a'}, // append only
+	{0x00000040, 'd'}, // no dump
+	{0x00000080, 'A'}, // no atime updates
+	{0x00000004, 'c'}, // compressed
+	{0x00000800, 'E'}, // encrypted
+	{0x00004000, 'j'}, // data journalling
+	{0x00001000, 'I'}, // indexed directory
+	{0x00008000, 't'}, // no tail merging
+	{0x00020000, 'T'}, // top of directory hierarchy
+	{0x00080000, 'e'}, // extents
+	{0x00800000, 'C'}, // no copy on write
+	{0x02000000, 'x'}, // direct access
+	{0x40000000, 'F'}, // casefolded directory
+	{0x10000000, 'N'}, // inline data
+	{0x20000000, 'P'}, // project hierarchy
+	{0x00100000, 'V'}, // verity
+	{0x00000400, 'm'}, // no compression
+}
+
+// FlagString renders chattr flags the way lsattr does, one letter or dash
+// per flag, followed by " cap" when the file has capabilities. Flags that
+// could not be read show as "?".
+func FlagString(flags uint32, known, hasCap bool) string {
+	s := "?"
+	if known {
+		b := make([]byte, len(inodeFlags))
+		for i, f := range inodeFlags {
+			b[i] = '-'
+			if flags&f.bit != 0 {
+				b[i] = f.letter
+			}
+		}
+		s = string(b)
+	}
+	if hasCap {
+		s += " cap"
+	}
+	return s
+}
+
 // FormatHardLinks renders a hard-link count for the long listing.
 func FormatHardLinks(n uint64) string { return strconv.FormatUint(n, 10) }
@@ -0,0 +1,25 @@
+package platform
+
+import (
+	iofs "io/fs"
+
+	"golang.org/x/sys/unix"
+)
+
+// readFlags returns the inode flags chattr sets, read with FS_IOC_GETFLAGS
+// like lsattr does. mode is that of the file path leads to. Only regular
+// files and directories are opened, so listing never blocks on a pipe or
+// wakes up a device; ok is false for the rest and on filesystems without
+// inode flags.
+func readFlags(path string, mode iofs.FileMode) (flags uint32, ok bool) {
+	if !mode.IsRegular() && !mode.IsDir() {
+		return 0, false
+	}
+	fd, err := unix.Open(path, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return 0, false
+	}
+	defer unix.Close(fd)
+	flags, err = unix.IoctlGetUint32(fd, unix.FS_IOC_GETFLAGS)
+	return flags, err == nil
+}
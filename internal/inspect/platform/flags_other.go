@@ -0,0 +1,10 @@
+//go:build !linux && !windows
+
+package platform
+
+import iofs "io/fs"
+
+// readFlags reports no inode flags: FS_IOC_GETFLAGS is Linux only.
+func readFlags(path string, mode iofs.FileMode) (flags uint32, ok bool) {
+	return 0, false
+}
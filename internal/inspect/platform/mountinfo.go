@@ -0,0 +1,53 @@
+package platform
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MountInfoPath is where Linux describes the mounts of the calling process.
+const MountInfoPath = "/proc/self/mountinfo"
+
+// ParseMountInfo reads the file system types out of a mountinfo file, by
+// the device number Stat.Dev holds for the files on each mount. Malformed
+// lines are skipped; when a device is mounted more than once the last
+// mount, the one on top, wins.
+func ParseMountInfo(data []byte) map[uint64]string {
+	types := make(map[uint64]string)
+	for _, line := range strings.Split(string(data), "\n") {
+		// 36 35 98:0 /mnt1 /mnt/parent rw,noatime master:1 - ext3 /dev/root rw
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		dev, ok := parseDev(fields[2])
+		if !ok {
+			continue
+		}
+		for i := 6; i < len(fields)-1; i++ {
+			if fields[i] == "-" {
+				types[dev] = fields[i+1]
+				break
+			}
+		}
+	}
+	return types
+}
+
+// parseDev turns mountinfo's "major:minor" into the st_dev value of the
+// files on that device, encoded as Linux does.
+func parseDev(s string) (uint64, bool) {
+	maj, min, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, false
+	}
+	major, err := strconv.ParseUint(maj, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	minor, err := strconv.ParseUint(min, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return minor&0xff | (major&0xfff)<<8 | (minor&^0xff)<<12 | (major&^0xfff)<<32, true
+}
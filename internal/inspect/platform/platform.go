@@ -6,6 +6,8 @@
 package platform
 
 import (
+	"strings"
+
 	"github.com/canta2899/logo-ls/pkg/fs"
 )
 
@@ -21,12 +23,101 @@ type Stat struct {
 	Sticky    bool // S_ISVTX
 	StickyX   bool // sticky AND other-executable (controls `t` vs `T`)
 	HasXAttr  bool // populated only when WantXAttr is set and the OS supports it
+	HasACL    bool // a POSIX ACL is set; populated along with HasXAttr
+	// Context is the SELinux security context; populated only when
+	// WantContext is set.
+	Context string
+	// Flags holds the Linux inode flags set by chattr, as returned by
+	// FS_IOC_GETFLAGS; FlagsKnown is false when they could not be read.
+	// HasCap marks files with capabilities. All three are populated only
+	// when WantFlags is set.
+	Flags      uint32
+	FlagsKnown bool
+	HasCap     bool
+	// XAttrs lists the extended attributes; populated only when ListXAttr
+	// is set.
+	XAttrs []XAttr
+	// Major and Minor split st_rdev for device files; zero otherwise.
+	Major, Minor uint32
+	// Dev is the device the file is on (st_dev); zero on Windows.
+	Dev uint64
+}
+
+// XAttr is one extended attribute of a file.
+type XAttr struct {
+	Name  string
+	Value []byte
 }
 
 // Options controls expensive optional lookups.
 type Options struct {
 	// WantXAttr asks the platform layer to call Listxattr (Unix only).
 	WantXAttr bool
+	// ListXAttr also reads the name and value of every attribute.
+	ListXAttr bool
+	// WantContext reads the SELinux context.
+	WantContext bool
+	// WantFlags reads the inode flags and looks for file capabilities,
+	// which costs an open and an ioctl per file (Linux only).
+	WantFlags bool
+}
+
+// Attribute names with a meaning of their own.
+const (
+	xattrACLAccess  = "system.posix_acl_access"
+	xattrACLDefault = "system.posix_acl_default"
+	xattrSELinux    = "security.selinux"
+	xattrCap        = "security.capability"
+)
+
+// markXAttrs sets HasXAttr and HasACL from the attribute names of a file.
+// The SELinux context is on every file of the systems using it and does
+// not earn the @ marker; ACLs get a marker of their own.
+func (s *Stat) markXAttrs(names []string) {
+	for _, name := range names {
+		switch {
+		case name == xattrACLAccess || name == xattrACLDefault:
+			s.HasACL = true
+		case name == xattrCap:
+			s.HasCap, s.HasXAttr = true, true
+		case name != xattrSELinux:
+			s.HasXAttr = true
+		}
+	}
+}
+
+// contextValue trims the NUL the kernel stores after a security context.
+func contextValue(v []byte) string {
+	return strings.TrimRight(string(v), "\x00")
+}
+
+// providerStat returns the Stat of a SysProvider with the optional fields
+// opts did not ask for cleared, as a real lookup would leave them.
+// HasXAttr, HasACL and Context are derived from XAttrs when the provider
+// lists attributes.
+func providerStat(sp SysProvider, opts Options) Stat {
+	s := sp.PlatformStat()
+	names := make([]string, 0, len(s.XAttrs))
+	for _, x := range s.XAttrs {
+		names = append(names, x.Name)
+		if x.Name == xattrSELinux && s.Context == "" {
+			s.Context = contextValue(x.Value)
+		}
+	}
+	s.markXAttrs(names)
+	if !opts.WantXAttr && !opts.ListXAttr {
+		s.HasXAttr, s.HasACL = false, false
+	}
+	if !opts.WantContext {
+		s.Context = ""
+	}
+	if !opts.WantFlags {
+		s.Flags, s.FlagsKnown, s.HasCap = 0, false, false
+	}
+	if !opts.ListXAttr {
+		s.XAttrs = nil
+	}
+	return s
 }
 
 // Reader is the platform-specific reader. On Unix it uses a sentinel that
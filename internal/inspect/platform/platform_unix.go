@@ -3,8 +3,12 @@
 package platform
 
 import (
+	"errors"
+	iofs "io/fs"
 	"os/user"
+	"slices"
 	"strconv"
+	"strings"
 	"syscall"
 
 	"github.com/canta2899/logo-ls/pkg/fs"
@@ -36,11 +40,7 @@ func (r *unixReader) Read(absPath string, fi fs.FileInfo, opts Options) Stat {
 	}
 	// fakefs supplies a SysProvider instead of a real *syscall.Stat_t.
 	if sp, ok := fi.(SysProvider); ok {
-		s := sp.PlatformStat()
-		if opts.WantXAttr {
-			s.HasXAttr = listXAttr(absPath)
-		}
-		return s
+		return providerStat(sp, opts)
 	}
 	st, ok := fi.Sys().(*syscall.Stat_t)
 	if !ok {
@@ -53,19 +53,99 @@ func (r *unixReader) Read(absPath string, fi fs.FileInfo, opts Options) Stat {
 		UID:       st.Uid,
 		GID:       st.Gid,
 		Sticky:    st.Mode&unix.S_ISVTX != 0,
+		Dev:       uint64(st.Dev),
 	}
 	if s.Sticky && st.Mode&unix.S_IXOTH != 0 {
 		s.StickyX = true
 	}
-	if opts.WantXAttr {
-		s.HasXAttr = listXAttr(absPath)
+	if typ := st.Mode & unix.S_IFMT; typ == unix.S_IFBLK || typ == unix.S_IFCHR {
+		s.Major, s.Minor = unix.Major(uint64(st.Rdev)), unix.Minor(uint64(st.Rdev))
+	}
+	noFollow := fi.Mode()&iofs.ModeSymlink != 0
+	switch {
+	case opts.ListXAttr:
+		s.XAttrs = readXAttrs(absPath, noFollow)
+		names := make([]string, 0, len(s.XAttrs))
+		for _, x := range s.XAttrs {
+			names = append(names, x.Name)
+		}
+		s.markXAttrs(names)
+	case opts.WantXAttr:
+		s.markXAttrs(xattrNames(absPath, noFollow))
+	}
+	if opts.WantContext {
+		s.Context = readContext(absPath, noFollow)
+	}
+	switch {
+	case !opts.WantFlags:
+		s.HasCap = false
+	case !opts.WantXAttr && !opts.ListXAttr:
+		// the attribute names were not listed
+		s.HasCap = slices.Contains(xattrNames(absPath, noFollow), xattrCap)
+		fallthrough
+	default:
+		s.Flags, s.FlagsKnown = readFlags(absPath, fi.Mode())
 	}
 	return s
 }
 
-func listXAttr(path string) bool {
-	count, err := unix.Listxattr(path, nil)
-	return err == nil && count > 0
+// readContext returns the SELinux context of path, or "" without one.
+func readContext(path string, noFollow bool) string {
+	get := unix.Getxattr
+	if noFollow {
+		get = unix.Lgetxattr
+	}
+	buf := make([]byte, 256)
+	n, err := get(path, xattrSELinux, buf)
+	if errors.Is(err, unix.ERANGE) {
+		if n, err = get(path, xattrSELinux, nil); err == nil {
+			buf = make([]byte, n)
+			n, err = get(path, xattrSELinux, buf)
+		}
+	}
+	if err != nil {
+		return ""
+	}
+	return contextValue(buf[:n])
+}
+
+// xattrNames returns the names of path's extended attributes; noFollow
+// reads those of a symlink itself.
+func xattrNames(path string, noFollow bool) []string {
+	list := unix.Listxattr
+	if noFollow {
+		list = unix.Llistxattr
+	}
+	size, err := list(path, nil)
+	if err != nil || size <= 0 {
+		return nil
+	}
+	buf := make([]byte, size)
+	if size, err = list(path, buf); err != nil || size <= 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(string(buf[:size]), "\x00"), "\x00")
+}
+
+// readXAttrs returns the extended attributes of path in the order the
+// filesystem lists them, or nil when it has none or they cannot be read.
+func readXAttrs(path string, noFollow bool) []XAttr {
+	get := unix.Getxattr
+	if noFollow {
+		get = unix.Lgetxattr
+	}
+	var attrs []XAttr
+	for _, name := range xattrNames(path, noFollow) {
+		attr := XAttr{Name: name}
+		if n, err := get(path, name, nil); err == nil && n > 0 {
+			value := make([]byte, n)
+			if n, err = get(path, name, value); err == nil {
+				attr.Value = value[:n]
+			}
+		}
+		attrs = append(attrs, attr)
+	}
+	return attrs
 }
 
 func (r *unixReader) LookupOwner(uid uint32) string {
@@ -24,7 +24,7 @@ func (winReader) Read(absPath string, fi fs.FileInfo, opts Options) Stat {
 		return Stat{}
 	}
 	if sp, ok := fi.(SysProvider); ok {
-		return sp.PlatformStat()
+		return providerStat(sp, opts)
 	}
 	if absPath == "" {
 		return Stat{}
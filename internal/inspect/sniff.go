@@ -0,0 +1,117 @@
+package inspect
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// sniffLen is how much of a file --sniff reads: enough for the magic
+// numbers below and a shebang line.
+const sniffLen = 256
+
+// magics maps leading bytes to the IconSet entry of the format.
+var magics = []struct {
+	prefix string
+	kind   string
+}{
+	{"\x7fELF", "exe"},
+	{"\xfe\xed\xfa\xce", "exe"}, // Mach-O 32-bit
+	{"\xce\xfa\xed\xfe", "exe"},
+	{"\xfe\xed\xfa\xcf", "exe"}, // Mach-O 64-bit
+	{"\xcf\xfa\xed\xfe", "exe"},
+	{"\xca\xfe\xba\xbe", "exe"}, // Mach-O universal
+	{"MZ", "exe"},               // PE and DOS executables
+	{"\x89PNG\r\n\x1a\n", "image"},
+	{"%PDF-", "pdf"},
+	{"\x1f\x8b", "zip"}, // gzip
+	{"PK\x03\x04", "zip"},
+	{"SQLite format 3\x00", "sqlite"},
+}
+
+// interpreters maps shebang interpreters, with any version suffix removed,
+// to IconSet entries.
+var interpreters = map[string]string{
+	"ash":     "console",
+	"bash":    "console",
+	"bun":     "bun",
+	"dash":    "console",
+	"fish":    "console",
+	"ksh":     "console",
+	"lua":     "lua",
+	"make":    "makefile",
+	"node":    "nodejs",
+	"nodejs":  "nodejs",
+	"perl":    "perl",
+	"php":     "php",
+	"pwsh":    "powershell",
+	"python":  "python",
+	"rscript": "r",
+	"ruby":    "ruby",
+	"sh":      "console",
+	"tclsh":   "tcl",
+	"zsh":     "console",
+}
+
+// sniffKind returns the IconSet entry for the format of head, the first
+// bytes of a file, or "" when it isn't recognised.
+func sniffKind(head []byte) string {
+	if bytes.HasPrefix(head, []byte("#!")) {
+		return interpreterKind(head[2:])
+	}
+	for _, m := range magics {
+		if bytes.HasPrefix(head, []byte(m.prefix)) {
+			return m.kind
+		}
+	}
+	return ""
+}
+
+// interpreterKind parses a shebang line, following /usr/bin/env to the
+// program it runs.
+func interpreterKind(line []byte) string {
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return ""
+	}
+	prog := baseName(fields[0])
+	if prog == "env" {
+		prog = ""
+		for _, f := range fields[1:] {
+			// skip env's options and variable assignments
+			if strings.HasPrefix(f, "-") || strings.Contains(f, "=") {
+				continue
+			}
+			prog = baseName(f)
+			break
+		}
+	}
+	return interpreters[strings.ToLower(strings.TrimRight(prog, "0123456789."))]
+}
+
+func baseName(p string) string {
+	return p[strings.LastIndexByte(p, '/')+1:]
+}
+
+// sniff reads the start of the file at absPath and returns the IconSet
+// entry for its format, or "" when it can't be read or isn't recognised.
+func (i *Inspector) sniff(absPath string) string {
+	f, err := i.fs.Open(absPath)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	r, ok := f.(io.Reader)
+	if !ok {
+		return ""
+	}
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return ""
+	}
+	return sniffKind(head[:n])
+}
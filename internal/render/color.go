@@ -0,0 +1,29 @@
+package render
+
+import (
+	"io"
+	"regexp"
+)
+
+// ansiRE matches the SGR escape sequences logo-ls writes.
+var ansiRE = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// noColorWriter drops color escapes from everything written through it.
+// Every write carries whole sequences: rows are flushed from a buffer and
+// headers are written with a single Fprintf.
+type noColorWriter struct {
+	w io.Writer
+}
+
+// WithoutColor wraps w so ANSI color sequences never reach it, for
+// --color=never and --color=auto when stdout is not a terminal.
+func WithoutColor(w io.Writer) io.Writer {
+	return noColorWriter{w: w}
+}
+
+func (n noColorWriter) Write(p []byte) (int, error) {
+	if _, err := n.w.Write(ansiRE.ReplaceAll(p, nil)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -18,14 +18,18 @@ type CTW interface {
 
 const standardTerminalWidth = 80
 
-func NewCTW(longMode, oneFilePerLine, icon bool) CTW {
-	if longMode {
-		return NewLongCTW(10)
+// NewCTW picks the writer for the listing mode: a LongCTW with the column
+// roles for long and one-per-line listings, the multi-column layout when
+// roles is nil. width is the terminal width for the multi-column layout; 0
+// or less queries the terminal.
+func NewCTW(roles []Role, width int) CTW {
+	if roles != nil {
+		return NewLongCTW(roles...)
 	}
-	if oneFilePerLine {
-		return NewLongCTW(4)
+	if width <= 0 {
+		width = GetCustomTerminalWidth()
 	}
-	return NewStandardCTW(GetCustomTerminalWidth())
+	return NewStandardCTW(width)
 }
 
 func GetCustomTerminalWidth() int {
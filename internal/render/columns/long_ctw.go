@@ -5,39 +5,89 @@ import (
 	"fmt"
 )
 
+// warnColor draws the --audit warnings column.
+const warnColor = "\033[38;2;229;057;053m"
+
+// Role tells LongCTW how to align and color a column.
+type Role int
+
+const (
+	// RoleRight is a plain right-aligned column, like sizes and dates.
+	RoleRight Role = iota
+	// RoleLeft is a plain left-aligned column, like the chattr flags.
+	RoleLeft
+	// RolePermissions is the left-aligned mode string, colored letter by
+	// letter after SetPermissionColors.
+	RolePermissions
+	// RoleWarnings is the left-aligned --audit warnings, in warnColor.
+	RoleWarnings
+	// RoleIcon is drawn in the color passed to AddRow.
+	RoleIcon
+	// RoleName is the left-aligned file name, in the color of the git
+	// status when the listing has one.
+	RoleName
+	// RoleGit is the git status, one per listing at most.
+	RoleGit
+)
+
 // LongCTW is the column writer for long mode listings.
 type LongCTW struct {
 	*baseCtw
 	rows         [][]string
 	columnWidths []int
 	iconColors   []string
-	// numCols = cols - 1; the icon and git status columns are handled separately.
-	numCols int
+	// details holds, per row, lines printed verbatim under it.
+	details [][]string
+	// permColors, when set, colors the permission column.
+	permColors *PermissionColors
+	// roles holds the role of each column, in AddRow order.
+	roles []Role
+	// gitCol is the index of the RoleGit column, -1 without one.
+	gitCol int
 }
 
-func NewLongCTW(cols int) *LongCTW {
-	return &LongCTW{
+// NewLongCTW returns a writer for rows of len(roles) columns, where
+// roles[i] sets how column i is drawn.
+func NewLongCTW(roles ...Role) *LongCTW {
+	l := &LongCTW{
 		baseCtw:      newBaseCtw(),
-		numCols:      cols - 1,
-		columnWidths: make([]int, cols),
+		roles:        roles,
+		gitCol:       -1,
+		columnWidths: make([]int, len(roles)),
 		rows:         make([][]string, 0),
 		iconColors:   make([]string, 0),
 	}
+	for i, role := range roles {
+		if role == RoleGit {
+			l.gitCol = i
+		}
+	}
+	return l
 }
 
 func (l *LongCTW) AddRow(color string, columns ...string) {
-	if len(columns) != l.numCols+1 {
+	if len(columns) != len(l.roles) {
 		return
 	}
 
 	for i, val := range columns {
-		if len(val) > l.columnWidths[i] {
-			l.columnWidths[i] = len(val)
+		if w := DisplayWidth(val); w > l.columnWidths[i] {
+			l.columnWidths[i] = w
 		}
 	}
 
 	l.rows = append(l.rows, columns)
 	l.iconColors = append(l.iconColors, color)
+	l.details = append(l.details, nil)
+}
+
+// AddDetail attaches lines to the last row added; they are printed under
+// it as they are, outside the column layout.
+func (l *LongCTW) AddDetail(lines ...string) {
+	if len(l.details) == 0 {
+		return
+	}
+	l.details[len(l.details)-1] = append(l.details[len(l.details)-1], lines...)
 }
 
 // Flush writes the table to buf, skipping zero-width columns.
@@ -49,11 +99,15 @@ func (l *LongCTW) Flush(buf *bytes.Buffer) {
 		}
 	}
 
-	l.columnWidths[l.numCols] = 1   // git column
-	l.columnWidths[l.numCols-2] = 1 // icon column
+	if l.gitCol >= 0 {
+		l.columnWidths[l.gitCol] = 1
+	}
 
 	for rowIdx, row := range l.rows {
 		l.writeRow(buf, rowIdx, row, skipCols)
+		for _, line := range l.details[rowIdx] {
+			fmt.Fprintln(buf, line)
+		}
 	}
 }
 
@@ -73,20 +127,29 @@ func (l *LongCTW) writeRow(buf *bytes.Buffer, rowIdx int, row []string, skipCols
 }
 
 func (l *LongCTW) writeCell(buf *bytes.Buffer, rowIdx, colIdx int, cellValue string, row []string, skipCols []bool) {
-	gitSkipped := skipCols[l.numCols]
 	width := l.columnWidths[colIdx]
 
-	switch {
-	case colIdx == l.numCols-2:
-		fmt.Fprintf(buf, "%s%*s%s", l.iconColors[rowIdx], width, cellValue, l.noColor)
-	case colIdx >= l.numCols-1 && !gitSkipped:
-		fmt.Fprintf(buf, "%s%-*s%s", l.GetGitColor(row[l.numCols]), width, cellValue, l.noColor)
-	case gitSkipped && colIdx == l.numCols-1:
-		fmt.Fprintf(buf, "%-*s", width, cellValue)
-	case !gitSkipped && colIdx == 1:
-		// permission column is left-aligned
-		fmt.Fprintf(buf, "%-*s", width, cellValue)
+	switch l.roles[colIdx] {
+	case RolePermissions:
+		if l.permColors == nil {
+			fmt.Fprint(buf, padRight(cellValue, width))
+			break
+		}
+		// padded by the width of the letters, not of their escapes
+		fmt.Fprint(buf, l.permColors.colorize(cellValue, l.noColor), padRight("", width-DisplayWidth(cellValue)))
+	case RoleLeft:
+		fmt.Fprint(buf, padRight(cellValue, width))
+	case RoleWarnings:
+		fmt.Fprintf(buf, "%s%s%s", warnColor, padRight(cellValue, width), l.noColor)
+	case RoleIcon:
+		fmt.Fprintf(buf, "%s%s%s", l.iconColors[rowIdx], padRight(cellValue, width), l.noColor)
+	case RoleName, RoleGit:
+		if l.gitCol < 0 || skipCols[l.gitCol] {
+			fmt.Fprint(buf, padRight(cellValue, width))
+			break
+		}
+		fmt.Fprintf(buf, "%s%s%s", l.GetGitColor(row[l.gitCol]), padRight(cellValue, width), l.noColor)
 	default:
-		fmt.Fprintf(buf, "%*s", width, cellValue)
+		fmt.Fprint(buf, padLeft(cellValue, width))
 	}
 }
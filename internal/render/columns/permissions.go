@@ -0,0 +1,77 @@
+package ctw
+
+import "strings"
+
+// PermissionColors holds the SGR sequences the long listing draws the
+// letters of the permission string in.
+type PermissionColors struct {
+	Read, Write, Exec string
+	Special           string // s, S, t and T
+	Unset             string // -
+	Dir, Link         string // the type letter of directories and symlinks
+	Other             string // other type letters, and the @ and + markers
+}
+
+// DarkPermissionColors and LightPermissionColors suit dark and light
+// terminal backgrounds.
+var (
+	DarkPermissionColors = PermissionColors{
+		Read:    "\033[38;2;249;226;175m",
+		Write:   "\033[38;2;243;139;168m",
+		Exec:    "\033[38;2;166;227;161m",
+		Special: "\033[38;2;203;166;247m",
+		Unset:   "\033[38;2;108;112;134m",
+		Dir:     "\033[38;2;137;180;250m",
+		Link:    "\033[38;2;148;226;213m",
+		Other:   "\033[38;2;250;179;135m",
+	}
+	LightPermissionColors = PermissionColors{
+		Read:    "\033[38;2;223;142;029m",
+		Write:   "\033[38;2;210;015;057m",
+		Exec:    "\033[38;2;064;160;043m",
+		Special: "\033[38;2;136;057;239m",
+		Unset:   "\033[38;2;140;143;161m",
+		Dir:     "\033[38;2;030;102;245m",
+		Link:    "\033[38;2;023;146;153m",
+		Other:   "\033[38;2;254;100;011m",
+	}
+)
+
+// SetPermissionColors draws the permission column a letter at a time in
+// the colors of p.
+func (l *LongCTW) SetPermissionColors(p PermissionColors) {
+	l.permColors = &p
+}
+
+// colorize returns the permission string perm with each letter wrapped in
+// its color.
+func (p *PermissionColors) colorize(perm, noColor string) string {
+	var b strings.Builder
+	for i, r := range perm {
+		color := p.Other
+		switch {
+		case r == ' ':
+			b.WriteRune(r)
+			continue
+		case r == '-':
+			color = p.Unset
+		case i == 0 && r == 'd':
+			color = p.Dir
+		case i == 0 && r == 'l':
+			color = p.Link
+		case i == 0:
+		case r == 'r':
+			color = p.Read
+		case r == 'w':
+			color = p.Write
+		case r == 'x':
+			color = p.Exec
+		case strings.ContainsRune("sStT", r):
+			color = p.Special
+		}
+		b.WriteString(color)
+		b.WriteRune(r)
+		b.WriteString(noColor)
+	}
+	return b.String()
+}
@@ -12,6 +12,7 @@ type StandardCTW struct {
 	*baseCtw
 	rows          [][]string
 	sizeWidths    []int
+	iconWidths    []int
 	nameWidths    []int
 	gitWidths     []int
 	iconColors    []string
@@ -27,6 +28,7 @@ func NewStandardCTW(termW int) *StandardCTW {
 		terminalWidth: termW,
 		rows:          make([][]string, 0),
 		sizeWidths:    make([]int, 0),
+		iconWidths:    make([]int, 0),
 		nameWidths:    make([]int, 0),
 		gitWidths:     make([]int, 0),
 		iconColors:    make([]string, 0),
@@ -40,7 +42,8 @@ func (s *StandardCTW) AddRow(color string, args ...string) {
 	}
 
 	s.sizeWidths = append(s.sizeWidths, len(args[0]))
-	s.nameWidths = append(s.nameWidths, len(args[2]))
+	s.iconWidths = append(s.iconWidths, DisplayWidth(args[1]))
+	s.nameWidths = append(s.nameWidths, DisplayWidth(args[2]))
 	s.gitWidths = append(s.gitWidths, len(args[3]))
 
 	if !s.showIcon && len(args[1]) > 0 {
@@ -133,6 +136,7 @@ func (s *StandardCTW) computeOptimalWidths(rowCount, columnPadding int) [][4]int
 
 func (s *StandardCTW) calcSubColumnWidths(begin, end int) [4]int {
 	maxSizeWidth := 0
+	maxIconWidth := 1
 	maxNameWidth := 0
 	maxGitWidth := 0
 
@@ -140,6 +144,9 @@ func (s *StandardCTW) calcSubColumnWidths(begin, end int) [4]int {
 		if s.sizeWidths[i] > maxSizeWidth {
 			maxSizeWidth = s.sizeWidths[i]
 		}
+		if s.iconWidths[i] > maxIconWidth {
+			maxIconWidth = s.iconWidths[i]
+		}
 		if s.nameWidths[i] > maxNameWidth {
 			maxNameWidth = s.nameWidths[i]
 		}
@@ -153,7 +160,7 @@ func (s *StandardCTW) calcSubColumnWidths(begin, end int) [4]int {
 		result[0] = maxSizeWidth + 1
 	}
 	if s.showIcon {
-		result[1] = 2
+		result[1] = maxIconWidth + 1
 	}
 	result[2] = maxNameWidth
 	if maxGitWidth > 0 {
@@ -168,18 +175,17 @@ func (s *StandardCTW) printRowCell(buf *bytes.Buffer, rowIndex int, colSizes [4]
 	}
 
 	if s.showIcon {
-		fmt.Fprintf(buf, "%s%1s%s%s",
+		fmt.Fprintf(buf, "%s%s%s%s",
 			s.iconColors[rowIndex],
-			s.rows[rowIndex][1],
+			padRight(s.rows[rowIndex][1], colSizes[1]-1),
 			s.noColor,
 			s.empty,
 		)
 	}
 
-	fmt.Fprintf(buf, "%s%-*s%s",
+	fmt.Fprintf(buf, "%s%s%s",
 		s.GetGitColor(s.rows[rowIndex][3]),
-		colSizes[2],
-		s.rows[rowIndex][2],
+		padRight(s.rows[rowIndex][2], colSizes[2]),
 		s.noColor,
 	)
 
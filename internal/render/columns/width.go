@@ -0,0 +1,101 @@
+package ctw
+
+import (
+	"strings"
+	"unicode"
+)
+
+// wideRanges lists the code points terminals draw two cells wide: East
+// Asian wide and fullwidth characters and the emoji with emoji
+// presentation used by the emoji icon set.
+var wideRanges = []struct{ lo, hi rune }{
+	{0x1100, 0x115F},
+	{0x231A, 0x231B},
+	{0x23E9, 0x23EC},
+	{0x23F0, 0x23F0},
+	{0x23F3, 0x23F3},
+	{0x25FD, 0x25FE},
+	{0x2614, 0x2615},
+	{0x2648, 0x2653},
+	{0x267F, 0x267F},
+	{0x2693, 0x2693},
+	{0x26A1, 0x26A1},
+	{0x26AA, 0x26AB},
+	{0x26BD, 0x26BE},
+	{0x26C4, 0x26C5},
+	{0x26CE, 0x26CE},
+	{0x26D4, 0x26D4},
+	{0x26EA, 0x26EA},
+	{0x26F2, 0x26F3},
+	{0x26F5, 0x26F5},
+	{0x26FA, 0x26FA},
+	{0x26FD, 0x26FD},
+	{0x2705, 0x2705},
+	{0x270A, 0x270B},
+	{0x2728, 0x2728},
+	{0x274C, 0x274C},
+	{0x274E, 0x274E},
+	{0x2753, 0x2755},
+	{0x2757, 0x2757},
+	{0x2795, 0x2797},
+	{0x27B0, 0x27B0},
+	{0x27BF, 0x27BF},
+	{0x2B1B, 0x2B1C},
+	{0x2B50, 0x2B50},
+	{0x2B55, 0x2B55},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x1F0CF, 0x1F0CF},
+	{0x1F300, 0x1F64F},
+	{0x1F680, 0x1F6FF},
+	{0x1F7E0, 0x1F7EB},
+	{0x1F900, 0x1F9FF},
+	{0x1FA70, 0x1FAFF},
+	{0x20000, 0x3FFFD},
+}
+
+// runeWidth returns the number of cells r occupies: 0 for combining marks,
+// variation selectors and zero-width joiners, 2 for wide characters and 1
+// otherwise. Nerd Font glyphs, in the private use areas, count as 1.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0x200D, r >= 0xFE00 && r <= 0xFE0F, unicode.Is(unicode.Mn, r):
+		return 0
+	}
+	for _, w := range wideRanges {
+		if r < w.lo {
+			break
+		}
+		if r <= w.hi {
+			return 2
+		}
+	}
+	return 1
+}
+
+// DisplayWidth returns the number of terminal cells s occupies.
+func DisplayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+// padRight left-aligns s in width cells.
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-DisplayWidth(s)))
+}
+
+// padLeft right-aligns s in width cells.
+func padLeft(s string, width int) string {
+	return strings.Repeat(" ", max(0, width-DisplayWidth(s))) + s
+}
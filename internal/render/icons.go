@@ -0,0 +1,62 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/canta2899/logo-ls/internal/icons"
+	"github.com/canta2899/logo-ls/internal/render/columns"
+)
+
+const noColor = "\033[0m"
+
+// WriteMappings writes one line per mapping for "logo-ls icons list" and
+// "search": the glyph in its color, the table, the key, the color as
+// #rrggbb and the file the mapping comes from. Colors are those for bg.
+func WriteMappings(w io.Writer, mappings []icons.Mapping, set icons.GlyphSet, bg icons.Background) error {
+	var glyphWidth, sectionWidth, keyWidth int
+	for _, m := range mappings {
+		glyphWidth = max(glyphWidth, ctw.DisplayWidth(m.Icon.GlyphIn(set)))
+		sectionWidth = max(sectionWidth, len(m.Section))
+		keyWidth = max(keyWidth, ctw.DisplayWidth(m.Key))
+	}
+	bw := bufio.NewWriter(w)
+	for _, m := range mappings {
+		fmt.Fprintf(bw, "%s %-*s %s %s %s\n",
+			coloredGlyph(m.Icon, set, bg, glyphWidth),
+			sectionWidth, m.Section,
+			m.Key+strings.Repeat(" ", keyWidth-ctw.DisplayWidth(m.Key)),
+			m.Icon.ColorFor(bg).Hex(), m.Source())
+	}
+	return bw.Flush()
+}
+
+// WriteExplanation writes how the icon of path was resolved, for "logo-ls
+// icons which".
+func WriteExplanation(w io.Writer, path string, x icons.Explanation, set icons.GlyphSet, bg icons.Background) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, path)
+	icon := coloredGlyph(x.Icon, set, bg, 0) + " " + x.Icon.ColorFor(bg).Hex()
+	if x.Icon.Name != "" {
+		icon += " " + x.Icon.Name
+	}
+	if x.Icon.IsExecutable {
+		icon += " (executable)"
+	}
+	fmt.Fprintf(bw, "  icon      %s\n", icon)
+	fmt.Fprintf(bw, "  built-in  %s: %s\n", x.Builtin.Section, x.Builtin.Key)
+	if x.Overridden {
+		fmt.Fprintf(bw, "  override  %s\n", x.Override)
+	}
+	return bw.Flush()
+}
+
+// coloredGlyph returns the icon's glyph in set, in its color on bg and
+// padded to width cells.
+func coloredGlyph(i *icons.IconInfo, set icons.GlyphSet, bg icons.Background, width int) string {
+	glyph := i.GlyphIn(set)
+	pad := strings.Repeat(" ", max(0, width-ctw.DisplayWidth(glyph)))
+	return i.ColorOn(bg) + glyph + noColor + pad
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/canta2899/logo-ls/internal/render/columns"
+	"github.com/canta2899/logo-ls/internal/icons"
 	"github.com/canta2899/logo-ls/internal/inspect"
 )
 
@@ -43,11 +44,51 @@ type Options struct {
 	ShowBlocks    bool
 	HumanReadable bool
 	TimeFormatter TimeFormatter
+	// TerminalWidth overrides the detected width of the multi-column
+	// layout; 0 detects it from the terminal.
+	TerminalWidth int
+	// Glyphs selects the glyph set icons are drawn from.
+	Glyphs icons.GlyphSet
+	// Background picks between an icon's dark and light colors.
+	Background icons.Background
+	// XAttrs lists each entry's extended attributes under it in the long
+	// listing; XAttrValues adds their values.
+	XAttrs      bool
+	XAttrValues bool
+	// ShowContext adds the SELinux context, as a column in the long
+	// listing and before the name otherwise.
+	ShowContext bool
+	// ShowFlags adds the chattr flags column to the long listing.
+	ShowFlags bool
+	// ShowFSType adds the file system type, as a column in the long
+	// listing and before the name otherwise.
+	ShowFSType bool
+	// OctalPermissions adds the permissions in octal after the mode
+	// string; PermissionColors colors the mode string letter by letter.
+	OctalPermissions bool
+	PermissionColors bool
+	// Audit shows each entry's --audit warnings, as a column before the
+	// icon in the long listing and before the name otherwise.
+	Audit bool
 }
 
 // Render writes one directory's worth of entries to w in the selected mode.
 func Render(w io.Writer, entries []*inspect.InspectedEntry, opts Options) {
-	tw := ctw.NewCTW(opts.Mode == ModeLong, opts.Mode == ModeOneFilePerLine, opts.ShowIcon)
+	var roles []ctw.Role
+	switch opts.Mode {
+	case ModeLong:
+		roles = longRoles
+	case ModeOneFilePerLine:
+		roles = lineRoles
+	}
+	tw := ctw.NewCTW(roles, opts.TerminalWidth)
+	if lw, ok := tw.(*ctw.LongCTW); ok && opts.PermissionColors {
+		palette := ctw.DarkPermissionColors
+		if opts.Background == icons.BackgroundLight {
+			palette = ctw.LightPermissionColors
+		}
+		lw.SetPermissionColors(palette)
+	}
 	for _, e := range entries {
 		addRow(tw, e, opts)
 	}
@@ -56,33 +97,82 @@ func Render(w io.Writer, entries []*inspect.InspectedEntry, opts Options) {
 	_, _ = io.Copy(w, buf)
 }
 
+// longRoles and lineRoles are the column roles of the rows addRow adds in
+// the long and one-per-line listings, in the same order.
+var (
+	longRoles = []ctw.Role{
+		ctw.RoleRight,       // block size and inode
+		ctw.RolePermissions, // mode string
+		ctw.RoleRight,       // octal permissions
+		ctw.RoleLeft,        // chattr flags
+		ctw.RoleRight,       // hard links
+		ctw.RoleRight,       // owner
+		ctw.RoleRight,       // group
+		ctw.RoleRight,       // author
+		ctw.RoleLeft,        // SELinux context
+		ctw.RoleLeft,        // file system type
+		ctw.RoleRight,       // size
+		ctw.RoleRight,       // modification time
+		ctw.RoleWarnings,    // --audit warnings
+		ctw.RoleIcon,
+		ctw.RoleName,
+		ctw.RoleGit,
+	}
+	lineRoles = []ctw.Role{ctw.RoleRight, ctw.RoleIcon, ctw.RoleName, ctw.RoleGit}
+)
+
 func addRow(tw ctw.CTW, e *inspect.InspectedEntry, opts Options) {
 	displayName := e.Base + e.Ext + e.Indicator
 	if opts.Mode == ModeLong {
 		tw.AddRow(
-			e.Icon.GetColor(),
+			e.Icon.ColorOn(opts.Background),
 			blockSizeWithInode(e, opts),
-			inspect.ModeString(e.Mode, e.Sticky, e.StickyX, e.HasXAttr),
+			inspect.ModeString(e.Mode, e.Sticky, e.StickyX, e.HasXAttr, e.HasACL),
+			octal(e, opts),
+			flags(e, opts),
 			strconv.FormatUint(hardLinks(e), 10),
 			e.Owner,
 			paddedGroup(e.Group),
-			formatSize(e.Size, opts.HumanReadable),
+			e.Author,
+			context(e, opts),
+			fsType(e, opts),
+			sizeColumn(e, opts.HumanReadable),
 			opts.TimeFormatter.Format(&e.ModTime),
-			e.Icon.GetGlyph(),
+			strings.Join(e.Warnings, ","),
+			e.Icon.GlyphIn(opts.Glyphs),
 			displayName,
 			e.GitStatus,
 		)
+		if opts.XAttrs && len(e.XAttrs) > 0 {
+			if lw, ok := tw.(*ctw.LongCTW); ok {
+				lw.AddDetail(xattrLines(e, opts.XAttrValues)...)
+			}
+		}
 		return
 	}
 	tw.AddRow(
-		e.Icon.GetColor(),
+		e.Icon.ColorOn(opts.Background),
 		blockSizeWithInode(e, opts),
-		e.Icon.GetGlyph(),
+		e.Icon.GlyphIn(opts.Glyphs),
 		displayName,
 		e.GitStatus,
 	)
 }
 
+// xattrLines formats e's extended attributes for --xattr, like the -@
+// option of BSD ls: a tab, the name, and the size of the value.
+func xattrLines(e *inspect.InspectedEntry, values bool) []string {
+	lines := make([]string, 0, len(e.XAttrs))
+	for _, x := range e.XAttrs {
+		line := fmt.Sprintf("\t%s\t%6d", x.Name, len(x.Value))
+		if values {
+			line += " " + strconv.Quote(string(x.Value))
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
 func hardLinks(e *inspect.InspectedEntry) uint64 {
 	if e.HardLinks == 0 {
 		return 1
@@ -108,5 +198,54 @@ func blockSizeWithInode(e *inspect.InspectedEntry, opts Options) string {
 	if opts.ShowBlocks {
 		parts = append(parts, formatSize(e.Blocks, opts.HumanReadable))
 	}
+	if opts.Mode != ModeLong && opts.ShowContext {
+		parts = append(parts, context(e, opts))
+	}
+	if opts.Mode != ModeLong && opts.ShowFSType {
+		parts = append(parts, fsType(e, opts))
+	}
+	if opts.Mode != ModeLong && opts.Audit && len(e.Warnings) > 0 {
+		parts = append(parts, strings.Join(e.Warnings, ","))
+	}
 	return strings.TrimSpace(strings.Join(parts, " "))
 }
+
+// octal returns e's --octal-permissions column.
+func octal(e *inspect.InspectedEntry, opts Options) string {
+	if !opts.OctalPermissions || e.Mode == 0 {
+		return ""
+	}
+	return inspect.OctalMode(e.Mode, e.Sticky)
+}
+
+// flags returns e's chattr flags column, empty unless opts.ShowFlags.
+func flags(e *inspect.InspectedEntry, opts Options) string {
+	if !opts.ShowFlags {
+		return ""
+	}
+	return inspect.FlagString(e.Flags, e.FlagsKnown, e.HasCap)
+}
+
+// context returns e's SELinux context for -Z, or "?" when it has none,
+// like GNU ls.
+func context(e *inspect.InspectedEntry, opts Options) string {
+	switch {
+	case !opts.ShowContext:
+		return ""
+	case e.Context == "":
+		return "?"
+	}
+	return e.Context
+}
+
+// fsType returns the type of the file system e is on for --fs-type, or
+// "?" when it is not known.
+func fsType(e *inspect.InspectedEntry, opts Options) string {
+	switch {
+	case !opts.ShowFSType:
+		return ""
+	case e.FSType == "":
+		return "?"
+	}
+	return e.FSType
+}
@@ -1,6 +1,19 @@
 package render
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/canta2899/logo-ls/internal/inspect"
+)
+
+// sizeColumn renders the long listing's size column: the device numbers
+// as "major, minor" for devices, like ls, and the size otherwise.
+func sizeColumn(e *inspect.InspectedEntry, humanReadable bool) string {
+	if e.IsDevice() {
+		return fmt.Sprintf("%d, %3d", e.Major, e.Minor)
+	}
+	return formatSize(e.Size, humanReadable)
+}
 
 // formatSize renders b as either a raw byte count or a human-readable
 // 1K/2.3M-style size when humanReadable is true.
@@ -3,9 +3,11 @@ package fakefs
 
 import (
 	"errors"
+	"io"
 	iofs "io/fs"
 	"maps"
 	"path"
+	"slices"
 	"sort"
 	"strings"
 	"syscall"
@@ -24,6 +26,14 @@ type Meta struct {
 	Nlinks  uint64
 	Blocks  int64
 	Mtime   time.Time
+	// XAttrs are the extended attributes, by name.
+	XAttrs map[string]string
+	// UID and GID are the numeric owner and group, shown with -n and when
+	// Owner or Group is empty.
+	UID, GID uint32
+	// Flags are the inode flags chattr sets, as FS_IOC_GETFLAGS returns
+	// them; reported for regular files and directories.
+	Flags uint32
 }
 
 // platformStat builds a platform.Stat from this Meta. Owner/Group are passed
@@ -34,10 +44,18 @@ func (m Meta) platformStat() platform.Stat {
 	if nlinks == 0 {
 		nlinks = 1
 	}
+	var xattrs []platform.XAttr
+	for _, name := range slices.Sorted(maps.Keys(m.XAttrs)) {
+		xattrs = append(xattrs, platform.XAttr{Name: name, Value: []byte(m.XAttrs[name])})
+	}
 	return platform.Stat{
 		Inode:     m.Inode,
 		HardLinks: nlinks,
 		Blocks:    m.Blocks,
+		XAttrs:    xattrs,
+		UID:       m.UID,
+		GID:       m.GID,
+		Flags:     m.Flags,
 	}
 }
 
@@ -57,9 +75,16 @@ type Entry struct {
 	meta     Meta
 	target   string   // for symlinks
 	children []*Entry // for dirs
+	data     []byte   // for files, see Contents
 
 	// Failures: makes ReadDir return EACCES on this directory.
 	unreadable bool
+
+	// Devices only: the device numbers.
+	major, minor uint32
+
+	// dev is the device the entry is on, see Mount.
+	dev uint64
 }
 
 // File creates a file entry.
@@ -101,12 +126,65 @@ func Symlink(name, target string, meta Meta) *Entry {
 	}
 }
 
+// BlockDevice creates a block device entry with the given device numbers.
+func BlockDevice(name string, major, minor uint32, meta Meta) *Entry {
+	return special(name, iofs.ModeDevice, major, minor, meta)
+}
+
+// CharDevice creates a character device entry with the given device
+// numbers.
+func CharDevice(name string, major, minor uint32, meta Meta) *Entry {
+	return special(name, iofs.ModeDevice|iofs.ModeCharDevice, major, minor, meta)
+}
+
+// Pipe creates a named pipe entry.
+func Pipe(name string, meta Meta) *Entry {
+	return special(name, iofs.ModeNamedPipe, 0, 0, meta)
+}
+
+// Socket creates a Unix domain socket entry.
+func Socket(name string, meta Meta) *Entry {
+	return special(name, iofs.ModeSocket, 0, 0, meta)
+}
+
+func special(name string, typ iofs.FileMode, major, minor uint32, meta Meta) *Entry {
+	meta.Mode = meta.Mode&^iofs.ModeType | typ
+	return &Entry{
+		kind:  kindFile,
+		name:  name,
+		mtime: meta.Mtime,
+		meta:  meta,
+		major: major,
+		minor: minor,
+	}
+}
+
 // Unreadable marks a directory as returning EACCES on ReadDir.
 func Unreadable(d *Entry) *Entry {
 	d.unreadable = true
 	return d
 }
 
+// Mount puts d and everything under it on device dev, making d a mount
+// point; mounts nested inside d keep their own device.
+func Mount(d *Entry, dev uint64) *Entry {
+	d.dev = dev
+	for _, c := range d.children {
+		if c.dev == 0 {
+			Mount(c, dev)
+		}
+	}
+	return d
+}
+
+// Contents gives a file entry data to return from ReadFile and sets its
+// size to match.
+func Contents(e *Entry, data string) *Entry {
+	e.data = []byte(data)
+	e.size = int64(len(data))
+	return e
+}
+
 // Option configures the fake filesystem.
 type Option func(*fakeFS)
 
@@ -115,6 +193,12 @@ func WithGitStatus(status map[string]string) Option {
 	return func(f *fakeFS) { f.gitStatus = status }
 }
 
+// WithMountInfo sets the contents ReadFile returns for
+// /proc/self/mountinfo, where the file system types are looked up.
+func WithMountInfo(data string) Option {
+	return func(f *fakeFS) { f.mountInfo = []byte(data) }
+}
+
 // New returns a fs.FS backed by the given spec rooted at "/".
 //
 // The provided root entry is mounted at "/<root.name>" (so paths look like
@@ -132,8 +216,16 @@ func New(root *Entry, opts ...Option) fs.FS {
 type fakeFS struct {
 	root      *Entry
 	gitStatus map[string]string
+	mountInfo []byte
 }
 
+// fakeFS implements every optional interface of fs, like the real one.
+var (
+	_ fs.ReadFileFS = (*fakeFS)(nil)
+	_ fs.ReadlinkFS = (*fakeFS)(nil)
+	_ fs.IsAbsFS    = (*fakeFS)(nil)
+)
+
 func (f *fakeFS) Join(parts ...string) string { return path.Join(parts...) }
 
 func (f *fakeFS) Separator() string { return "/" }
@@ -144,6 +236,8 @@ func (f *fakeFS) Dir(p string) string { return path.Dir(p) }
 
 func (f *fakeFS) Ext(p string) string { return path.Ext(p) }
 
+func (f *fakeFS) IsAbs(p string) bool { return path.IsAbs(p) }
+
 func (f *fakeFS) FromSlash(p string) string { return p }
 
 func (f *fakeFS) Rel(base, target string) (string, error) {
@@ -166,6 +260,15 @@ func (f *fakeFS) Abs(p string) (string, error) {
 }
 
 func (f *fakeFS) lookup(p string) (*Entry, error) {
+	e, _, err := f.lookupHops(p, 0)
+	return e, err
+}
+
+// lookupHops is lookup with hops symlinks already followed; symlinks to
+// directories in the middle of p are followed, as by the kernel. It also
+// returns the path of the entry with those symlinks resolved, so a ".."
+// after one climbs out of the directory the link points to.
+func (f *fakeFS) lookupHops(p string, hops int) (*Entry, string, error) {
 	p = path.Clean(p)
 	if p == "/" {
 		// Synthetic root above the mounted entry. Returns dir metadata so
@@ -181,16 +284,24 @@ func (f *fakeFS) lookup(p string) (*Entry, error) {
 				Inode: "1", Nlinks: 1, Mtime: rootMtime,
 			},
 			children: []*Entry{f.root},
-		}, nil
+		}, "/", nil
 	}
 	parts := strings.Split(strings.TrimPrefix(p, "/"), "/")
 	if parts[0] != f.root.name {
-		return nil, &iofs.PathError{Op: "stat", Path: p, Err: iofs.ErrNotExist}
+		return nil, "", &iofs.PathError{Op: "stat", Path: p, Err: iofs.ErrNotExist}
 	}
 	cur := f.root
+	curPath := "/" + f.root.name
 	for _, part := range parts[1:] {
+		if cur.kind == kindSymlink {
+			realPath, target, err := f.resolveSymlinkHops(curPath, cur, hops+1)
+			if err != nil {
+				return nil, "", err
+			}
+			cur, curPath = target, realPath
+		}
 		if cur.kind != kindDir {
-			return nil, &iofs.PathError{Op: "stat", Path: p, Err: iofs.ErrNotExist}
+			return nil, "", &iofs.PathError{Op: "stat", Path: p, Err: iofs.ErrNotExist}
 		}
 		var next *Entry
 		for _, c := range cur.children {
@@ -200,28 +311,46 @@ func (f *fakeFS) lookup(p string) (*Entry, error) {
 			}
 		}
 		if next == nil {
-			return nil, &iofs.PathError{Op: "stat", Path: p, Err: iofs.ErrNotExist}
+			return nil, "", &iofs.PathError{Op: "stat", Path: p, Err: iofs.ErrNotExist}
 		}
 		cur = next
+		curPath = path.Join(curPath, part)
 	}
-	return cur, nil
+	return cur, curPath, nil
 }
 
-// resolveSymlink follows a symlink relative to its parent directory.
+// maxLinkHops is how many symlinks a lookup follows before failing with
+// ELOOP, as on Linux.
+const maxLinkHops = 40
+
+// resolveSymlink follows a symlink relative to its parent directory and
+// returns the resolved path and entry.
 func (f *fakeFS) resolveSymlink(linkPath string, e *Entry) (string, *Entry, error) {
+	if _, realPath, err := f.lookupHops(linkPath, 0); err == nil {
+		linkPath = realPath
+	}
+	return f.resolveSymlinkHops(linkPath, e, 1)
+}
+
+// resolveSymlinkHops is resolveSymlink for a link at linkPath, a path
+// without symlinks, with hops symlinks already followed.
+func (f *fakeFS) resolveSymlinkHops(linkPath string, e *Entry, hops int) (string, *Entry, error) {
+	if hops > maxLinkHops {
+		return linkPath, nil, &iofs.PathError{Op: "stat", Path: linkPath, Err: syscall.ELOOP}
+	}
 	target := e.target
 	if !path.IsAbs(target) {
 		target = path.Join(path.Dir(linkPath), target)
 	}
 	target = path.Clean(target)
-	resolved, err := f.lookup(target)
+	resolved, realPath, err := f.lookupHops(target, hops)
 	if err != nil {
 		return target, nil, err
 	}
 	if resolved.kind == kindSymlink {
-		return f.resolveSymlink(target, resolved)
+		return f.resolveSymlinkHops(realPath, resolved, hops+1)
 	}
-	return target, resolved, nil
+	return realPath, resolved, nil
 }
 
 func (f *fakeFS) Stat(p string) (fs.FileInfo, error) {
@@ -234,7 +363,8 @@ func (f *fakeFS) Stat(p string) (fs.FileInfo, error) {
 		if err != nil {
 			return nil, err
 		}
-		return &fakeFileInfo{entry: target}, nil
+		// like os.Stat, the info is named after the link
+		return &fakeFileInfo{entry: target, name: e.name}, nil
 	}
 	return &fakeFileInfo{entry: e}, nil
 }
@@ -267,6 +397,11 @@ func (f *fakeFS) ReadDir(p string) ([]fs.DirEntry, error) {
 	if err != nil {
 		return nil, err
 	}
+	if e.kind == kindSymlink {
+		if _, e, err = f.resolveSymlink(p, e); err != nil {
+			return nil, err
+		}
+	}
 	if e.kind != kindDir {
 		return nil, &iofs.PathError{Op: "readdir", Path: p, Err: errors.New("not a directory")}
 	}
@@ -283,13 +418,32 @@ func (f *fakeFS) ReadDir(p string) ([]fs.DirEntry, error) {
 	return out, nil
 }
 
-func (f *fakeFS) EvalSymlinks(p string) (string, error) {
+func (f *fakeFS) ReadFile(p string) ([]byte, error) {
+	if path.Clean(p) == platform.MountInfoPath && f.mountInfo != nil {
+		return append([]byte(nil), f.mountInfo...), nil
+	}
 	e, err := f.lookup(p)
+	if err != nil {
+		return nil, err
+	}
+	if e.kind == kindSymlink {
+		if _, e, err = f.resolveSymlink(p, e); err != nil {
+			return nil, err
+		}
+	}
+	if e.kind == kindDir {
+		return nil, &iofs.PathError{Op: "read", Path: p, Err: syscall.EISDIR}
+	}
+	return append([]byte(nil), e.data...), nil
+}
+
+func (f *fakeFS) EvalSymlinks(p string) (string, error) {
+	e, realPath, err := f.lookupHops(p, 0)
 	if err != nil {
 		return "", err
 	}
 	if e.kind != kindSymlink {
-		return path.Clean(p), nil
+		return realPath, nil
 	}
 	target, _, err := f.resolveSymlink(p, e)
 	if err != nil {
@@ -298,6 +452,17 @@ func (f *fakeFS) EvalSymlinks(p string) (string, error) {
 	return target, nil
 }
 
+func (f *fakeFS) Readlink(p string) (string, error) {
+	e, err := f.lookup(p)
+	if err != nil {
+		return "", err
+	}
+	if e.kind != kindSymlink {
+		return "", &iofs.PathError{Op: "readlink", Path: p, Err: syscall.EINVAL}
+	}
+	return e.target, nil
+}
+
 func (f *fakeFS) GitStatus(dir string) map[string]string {
 	if len(f.gitStatus) == 0 {
 		return nil
@@ -309,9 +474,16 @@ func (f *fakeFS) GitStatus(dir string) map[string]string {
 
 type fakeFileInfo struct {
 	entry *Entry
+	name  string // when set, the name of the symlink entry was reached by
+}
+
+func (fi *fakeFileInfo) Name() string {
+	if fi.name != "" {
+		return fi.name
+	}
+	return fi.entry.name
 }
 
-func (fi *fakeFileInfo) Name() string        { return fi.entry.name }
 func (fi *fakeFileInfo) Size() int64         { return fi.entry.size }
 func (fi *fakeFileInfo) Mode() iofs.FileMode { return fi.entry.meta.Mode }
 func (fi *fakeFileInfo) ModTime() time.Time  { return fi.entry.mtime }
@@ -321,7 +493,11 @@ func (fi *fakeFileInfo) Sys() any            { return nil }
 // PlatformStat satisfies platform.SysProvider so the inspector can pull
 // fakefs metadata without inventing a real *syscall.Stat_t.
 func (fi *fakeFileInfo) PlatformStat() platform.Stat {
-	return fi.entry.meta.platformStat()
+	s := fi.entry.meta.platformStat()
+	s.Major, s.Minor = fi.entry.major, fi.entry.minor
+	s.Dev = fi.entry.dev
+	s.FlagsKnown = fi.Mode().IsRegular() || fi.IsDir()
+	return s
 }
 
 // OwnerName returns the configured owner name on the fake entry. Used by the
@@ -352,10 +528,23 @@ type fakeFile struct {
 	fs      *fakeFS
 	entry   *Entry
 	absPath string
+	offset  int // read position in entry.data
 }
 
 func (fl *fakeFile) Name() string { return fl.absPath }
 
+func (fl *fakeFile) Read(b []byte) (int, error) {
+	if fl.entry.kind == kindDir {
+		return 0, &iofs.PathError{Op: "read", Path: fl.absPath, Err: syscall.EISDIR}
+	}
+	if fl.offset >= len(fl.entry.data) {
+		return 0, io.EOF
+	}
+	n := copy(b, fl.entry.data[fl.offset:])
+	fl.offset += n
+	return n, nil
+}
+
 func (fl *fakeFile) Stat() (fs.FileInfo, error) {
 	return &fakeFileInfo{entry: fl.entry}, nil
 }
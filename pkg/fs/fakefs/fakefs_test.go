@@ -1,7 +1,10 @@
 package fakefs
 
 import (
+	"errors"
+	"io"
 	"io/fs"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -78,6 +81,33 @@ func TestUnreadableDirectory(t *testing.T) {
 	}
 }
 
+func TestReadFile(t *testing.T) {
+	spec := Dir("root", defaultDirMeta("1"),
+		Contents(File("a.yaml", 0, mtime("2026-01-01 00:00:00"), defaultFileMeta("2")), "sort: time\n"),
+		Symlink("link", "a.yaml", defaultFileMeta("3")),
+	)
+	f := New(spec)
+
+	for _, p := range []string{"/root/a.yaml", "/root/link"} {
+		data, err := f.(*fakeFS).ReadFile(p)
+		if err != nil {
+			t.Fatalf("read %s: %v", p, err)
+		}
+		if string(data) != "sort: time\n" {
+			t.Errorf("read %s returned %q", p, data)
+		}
+	}
+	if fi, _ := f.Stat("/root/a.yaml"); fi.Size() != int64(len("sort: time\n")) {
+		t.Errorf("size %d doesn't match the contents", fi.Size())
+	}
+	if _, err := f.(*fakeFS).ReadFile("/root"); err == nil {
+		t.Error("expected an error reading a directory")
+	}
+	if _, err := f.(*fakeFS).ReadFile("/root/missing"); err == nil {
+		t.Error("expected an error reading a missing file")
+	}
+}
+
 func TestSymlinkResolution(t *testing.T) {
 	spec := Dir("root", defaultDirMeta("1"),
 		File("target.txt", 50, mtime("2026-01-01 00:00:00"), defaultFileMeta("2")),
@@ -102,6 +132,46 @@ func TestSymlinkResolution(t *testing.T) {
 	}
 }
 
+func TestSymlinkUnderSymlinkedDir(t *testing.T) {
+	f := New(Dir("root", defaultDirMeta("1"),
+		Dir("real", defaultDirMeta("2"),
+			Dir("sub", defaultDirMeta("3"),
+				Symlink("link", "../target.txt", defaultFileMeta("4")),
+			),
+			File("target.txt", 5, time.Time{}, defaultFileMeta("5")),
+		),
+		Symlink("alias", "real/sub", defaultFileMeta("6")),
+	))
+	// ".." climbs out of real/sub, where alias points, not out of alias
+	resolved, err := f.EvalSymlinks("/root/alias/link")
+	if err != nil {
+		t.Fatalf("eval: %v", err)
+	}
+	if resolved != "/root/real/target.txt" {
+		t.Errorf("eval returned %q", resolved)
+	}
+	if _, err := f.Stat("/root/alias/link"); err != nil {
+		t.Errorf("stat: %v", err)
+	}
+}
+
+func TestReadlinkAndLoops(t *testing.T) {
+	f := New(Dir("root", defaultDirMeta("1"),
+		Symlink("link", "../root/target.txt", defaultFileMeta("2")),
+		Symlink("a", "b", defaultFileMeta("3")),
+		Symlink("b", "a", defaultFileMeta("4")),
+	))
+	if target, err := f.(*fakeFS).Readlink("/root/link"); err != nil || target != "../root/target.txt" {
+		t.Errorf("readlink returned %q, %v", target, err)
+	}
+	if _, err := f.(*fakeFS).Readlink("/root"); !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("expected EINVAL reading a directory as a link, got %v", err)
+	}
+	if _, err := f.Stat("/root/a"); !errors.Is(err, syscall.ELOOP) {
+		t.Errorf("expected ELOOP, got %v", err)
+	}
+}
+
 // TestPlatformStatExposesMetadata checks that fakeFileInfo surfaces the
 // inode / hardlinks / blocks / owner / group fields via the SysProvider and
 // namedOwner interfaces, which is how the inspector reads them now.
@@ -160,3 +230,32 @@ func TestGitStatus(t *testing.T) {
 		t.Errorf("git status = %v", got)
 	}
 }
+
+func TestOpenRead(t *testing.T) {
+	spec := Dir("root", defaultDirMeta("1"),
+		Contents(File("a.txt", 0, mtime("2026-01-01 00:00:00"), defaultFileMeta("2")), "hello world"),
+	)
+	f := New(spec)
+
+	file, err := f.Open("/root/a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	head := make([]byte, 5)
+	if n, err := file.(io.Reader).Read(head); err != nil || string(head[:n]) != "hello" {
+		t.Fatalf("first read returned %q, %v", head[:n], err)
+	}
+	rest, err := io.ReadAll(file.(io.Reader))
+	if err != nil || string(rest) != " world" {
+		t.Errorf("rest returned %q, %v", rest, err)
+	}
+
+	dir, err := f.Open("/root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dir.(io.Reader).Read(head); err == nil {
+		t.Error("expected an error reading a directory")
+	}
+}
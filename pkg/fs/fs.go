@@ -2,7 +2,10 @@
 package fs
 
 import (
+	"errors"
+	"io"
 	iofs "io/fs"
+	"path/filepath"
 )
 
 // FS is the narrow filesystem abstraction. It exposes only real OS
@@ -54,3 +57,66 @@ type File interface {
 	ReadDir(n int) ([]DirEntry, error)
 	Close() error
 }
+
+// The interfaces below are optional: an FS that lacks one still works,
+// with the fallback of the function of the same name. Likewise a File may
+// implement io.Reader, which ReadFile and content sniffing need.
+
+// ReadFileFS is an FS that reads whole files.
+type ReadFileFS interface {
+	FS
+	ReadFile(path string) ([]byte, error)
+}
+
+// ReadlinkFS is an FS that reads symlink targets.
+type ReadlinkFS interface {
+	FS
+	// Readlink returns the target of the symlink at path as stored, which
+	// may be relative to the link's directory.
+	Readlink(path string) (string, error)
+}
+
+// IsAbsFS is an FS with its own rule for absolute paths.
+type IsAbsFS interface {
+	FS
+	IsAbs(path string) bool
+}
+
+// ErrUnsupported is returned when an FS lacks an optional operation.
+var ErrUnsupported = errors.New("operation not supported by this file system")
+
+// ReadFile reads the file at path with fsys's ReadFile, or through Open
+// when the file it returns is an io.Reader.
+func ReadFile(fsys FS, path string) ([]byte, error) {
+	if rf, ok := fsys.(ReadFileFS); ok {
+		return rf.ReadFile(path)
+	}
+	f, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r, ok := f.(io.Reader)
+	if !ok {
+		return nil, &iofs.PathError{Op: "read", Path: path, Err: ErrUnsupported}
+	}
+	return io.ReadAll(r)
+}
+
+// Readlink returns the target of the symlink at path, or ErrUnsupported
+// when fsys cannot read links.
+func Readlink(fsys FS, path string) (string, error) {
+	if rl, ok := fsys.(ReadlinkFS); ok {
+		return rl.Readlink(path)
+	}
+	return "", &iofs.PathError{Op: "readlink", Path: path, Err: ErrUnsupported}
+}
+
+// IsAbs reports whether path is absolute, by the rules of the OS unless
+// fsys has its own.
+func IsAbs(fsys FS, path string) bool {
+	if ia, ok := fsys.(IsAbsFS); ok {
+		return ia.IsAbs(path)
+	}
+	return filepath.IsAbs(path)
+}
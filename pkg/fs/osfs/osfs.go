@@ -16,6 +16,13 @@ func New() fs.FS {
 
 type osFS struct{}
 
+// osFS implements every optional interface of fs.
+var (
+	_ fs.ReadFileFS = (*osFS)(nil)
+	_ fs.ReadlinkFS = (*osFS)(nil)
+	_ fs.IsAbsFS    = (*osFS)(nil)
+)
+
 func (o *osFS) Abs(path string) (string, error) { return filepath.Abs(path) }
 
 func (o *osFS) Open(path string) (fs.File, error) {
@@ -54,10 +61,14 @@ func (o *osFS) ReadDir(path string) ([]fs.DirEntry, error) {
 	return out, err
 }
 
+func (o *osFS) ReadFile(path string) ([]byte, error) { return os.ReadFile(path) }
+
 func (o *osFS) EvalSymlinks(path string) (string, error) {
 	return filepath.EvalSymlinks(path)
 }
 
+func (o *osFS) Readlink(path string) (string, error) { return os.Readlink(path) }
+
 func (o *osFS) Join(parts ...string) string { return filepath.Join(parts...) }
 
 func (o *osFS) Separator() string { return string(os.PathSeparator) }
@@ -68,6 +79,8 @@ func (o *osFS) Dir(path string) string { return filepath.Dir(path) }
 
 func (o *osFS) Ext(path string) string { return filepath.Ext(path) }
 
+func (o *osFS) IsAbs(path string) bool { return filepath.IsAbs(path) }
+
 func (o *osFS) Rel(base, target string) (string, error) {
 	return filepath.Rel(base, target)
 }
@@ -41,7 +41,7 @@ func TestArgs_TwoDirectories(t *testing.T) {
 }
 
 func TestArgs_DirOrderingIsSorted(t *testing.T) {
-	// FileList is sorted before processing, so passing args in reverse should
+	// Operands are sorted before processing, so passing args in reverse should
 	// produce the same output as passing them in sorted order.
 	sorted := runApp(t, fakefs.New(twoDirRoot()), "-1e", "/root/alpha", "/root/beta").Stdout
 	reversed := runApp(t, fakefs.New(twoDirRoot()), "-1e", "/root/beta", "/root/alpha").Stdout
@@ -52,3 +52,65 @@ func TestArgs_DirOrderingIsSorted(t *testing.T) {
 		t.Errorf("expected /root/alpha before /root/beta, got:\n%s", sorted)
 	}
 }
+
+// datedDirRoot has three directories whose mtimes disagree with their
+// alphabetical order, so -t and -U produce distinct operand orderings.
+func datedDirRoot() *fakefs.Entry {
+	meta := func(inode, ts string) fakefs.Meta {
+		m := dirMeta(inode)
+		m.Mtime = mtime(ts)
+		return m
+	}
+	return fakefs.Dir("root", dirMeta("110"),
+		fakefs.Dir("alpha", meta("111", "2026-01-01 10:00:00")),
+		fakefs.Dir("beta", meta("112", "2026-03-01 10:00:00")),
+		fakefs.Dir("gamma", meta("113", "2026-02-01 10:00:00")),
+	)
+}
+
+// dirHeaders returns the "<path>:" banner lines in output order.
+func dirHeaders(out string) []string {
+	var got []string
+	for _, l := range lines(out) {
+		if strings.HasSuffix(l, ":") {
+			got = append(got, strings.TrimSuffix(l, ":"))
+		}
+	}
+	return got
+}
+
+func TestArgs_DirOperandsFollowSortMode(t *testing.T) {
+	cases := []struct {
+		flags string
+		args  []string
+		want  []string
+	}{
+		{"-1e", []string{"/root/gamma", "/root/alpha", "/root/beta"},
+			[]string{"/root/alpha", "/root/beta", "/root/gamma"}},
+		{"-1er", []string{"/root/gamma", "/root/alpha", "/root/beta"},
+			[]string{"/root/gamma", "/root/beta", "/root/alpha"}},
+		{"-1et", []string{"/root/alpha", "/root/gamma", "/root/beta"},
+			[]string{"/root/beta", "/root/gamma", "/root/alpha"}},
+		{"-1etr", []string{"/root/alpha", "/root/gamma", "/root/beta"},
+			[]string{"/root/alpha", "/root/gamma", "/root/beta"}},
+		{"-1eU", []string{"/root/gamma", "/root/alpha", "/root/beta"},
+			[]string{"/root/gamma", "/root/alpha", "/root/beta"}},
+	}
+	for _, c := range cases {
+		r := runApp(t, fakefs.New(datedDirRoot()), append([]string{c.flags}, c.args...)...)
+		assertExitCode(t, cli.CodeOk, r.ExitCode)
+		if got := dirHeaders(r.Stdout); !equalSlice(got, c.want) {
+			t.Errorf("%s %v: got %v, want %v", c.flags, c.args, got, c.want)
+		}
+	}
+}
+
+func TestArgs_FileOperandsKeepCommandLineOrderWithU(t *testing.T) {
+	vfs := fakefs.New(smallTree())
+	r := runApp(t, vfs, "-1eU", "/root/notes.txt", "/root/README.md")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	want := []string{"notes.txt", "README.md"}
+	if got := lines(r.Stdout); !equalSlice(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,113 @@
+package tests
+
+import (
+	iofs "io/fs"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/canta2899/logo-ls/internal/cli"
+	"github.com/canta2899/logo-ls/pkg/fs/fakefs"
+)
+
+// auditTree is a home directory with one file for each audit finding.
+// Files are owned by the user running the tests unless noted.
+func auditTree() *fakefs.Entry {
+	own := func(m fakefs.Meta, mode iofs.FileMode) fakefs.Meta {
+		m.UID = uint32(os.Getuid())
+		if mode != 0 {
+			m.Mode = mode
+		}
+		return m
+	}
+	foreign := own(fileMeta("8010"), 0)
+	foreign.UID++
+	return fakefs.Dir("root", own(dirMeta("8000"), 0),
+		fakefs.File("notes.txt", 10, mtime("2026-01-01 10:00:00"), own(fileMeta("8001"), 0o644)),
+		fakefs.File("shared.log", 10, mtime("2026-01-01 10:00:00"), own(fileMeta("8002"), 0o666)),
+		fakefs.File("helper", 10, mtime("2026-01-01 10:00:00"), own(execMeta("8003"), iofs.ModeSetuid|0o755)),
+		fakefs.File("mailer", 10, mtime("2026-01-01 10:00:00"), own(execMeta("8004"), iofs.ModeSetgid|0o755)),
+		fakefs.File("planted.sh", 10, mtime("2026-01-01 10:00:00"), foreign),
+		fakefs.Dir("drop", own(dirMeta("8005"), iofs.ModeDir|0o777)),
+		fakefs.Dir("tmp", own(dirMeta("8006"), iofs.ModeDir|iofs.ModeSticky|0o777)),
+		fakefs.Dir(".ssh", own(dirMeta("8007"), iofs.ModeDir|0o700),
+			fakefs.File("id_ed25519", 10, mtime("2026-01-01 10:00:00"), own(fileMeta("8008"), 0o620)),
+			fakefs.File("known_hosts", 10, mtime("2026-01-01 10:00:00"), own(fileMeta("8009"), 0o664)),
+		),
+		fakefs.Symlink("inside", "notes.txt", own(symlinkMeta("8011"), 0)),
+		fakefs.Symlink("outside", "/etc/shadow", own(symlinkMeta("8012"), 0)),
+	)
+}
+
+func TestAudit_Findings(t *testing.T) {
+	t.Setenv("HOME", "/root")
+	vfs := fakefs.New(auditTree())
+
+	r := runApp(t, vfs, "-le", "--audit", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: each risky file is flagged in the column before its name.
+	for name, warning := range map[string]string{
+		"shared.log": "world-writable",
+		"helper":     "setuid",
+		"mailer":     "setgid",
+		"planted.sh": "foreign-owner",
+		"drop/":      "no-sticky",
+		"outside":    "link-outside",
+	} {
+		assertContainsLine(t, r.Stdout, ` `+warning+` +`+strings.ReplaceAll(name, ".", `\.`))
+	}
+	// Intent: sticky world-writable directories like /tmp, and links that
+	// stay in the tree, are fine.
+	assertContainsLine(t, r.Stdout, `:\d\d {16}tmp/$`)
+	assertContainsLine(t, r.Stdout, `:\d\d {16}inside ~> notes\.txt$`)
+	assertContainsLine(t, r.Stdout, `:\d\d {16}notes\.txt$`)
+	// Intent: a summary follows the listing.
+	assertContains(t, r.Stdout, "\naudit: 6 findings in 6 files\n"+
+		"  world-writable     1\n"+
+		"  no-sticky          1\n"+
+		"  setuid             1\n"+
+		"  setgid             1\n"+
+		"  foreign-owner      1\n"+
+		"  link-outside       1\n")
+
+	r = runApp(t, vfs, "-1e", "--audit", "/root/.ssh")
+	// Intent: keys others may write to are flagged; known_hosts is not a key.
+	assertContainsLine(t, r.Stdout, `^ssh-key-writable id_ed25519$`)
+	assertContainsLine(t, r.Stdout, `^ +known_hosts$`)
+	assertContains(t, r.Stdout, "audit: 1 finding in 1 file\n")
+}
+
+func TestAudit_OffByDefault(t *testing.T) {
+	t.Setenv("HOME", "/root")
+	vfs := fakefs.New(auditTree())
+	r := runApp(t, vfs, "-le", "/root")
+	assertNotContains(t, r.Stdout, "world-writable")
+	assertNotContains(t, r.Stdout, "audit:")
+
+	// Intent: outside the home directory ownership is not judged.
+	t.Setenv("HOME", "/home/someone")
+	r = runApp(t, vfs, "-le", "--audit", "/root")
+	assertNotContains(t, r.Stdout, "foreign-owner")
+}
+
+func TestAudit_ThroughSymlinkedOperand(t *testing.T) {
+	t.Setenv("HOME", "/home/someone")
+	vfs := fakefs.New(fakefs.Dir("root", dirMeta("8100"),
+		fakefs.Dir("real", dirMeta("8101"),
+			fakefs.File("notes.txt", 10, mtime("2026-01-01 10:00:00"), fileMeta("8102")),
+			fakefs.Symlink("inside", "notes.txt", symlinkMeta("8103")),
+			fakefs.Symlink("outside", "/etc/shadow", symlinkMeta("8104")),
+		),
+		fakefs.Symlink("alias", "real", symlinkMeta("8105")),
+	))
+
+	// Intent: links are judged against the real directory behind the
+	// operand, so ones staying in it are fine when listed through a link.
+	for _, operand := range []string{"/root/alias", "/root/alias/"} {
+		r := runApp(t, vfs, "-lHe", "--audit", operand)
+		assertExitCode(t, cli.CodeOk, r.ExitCode)
+		assertContainsLine(t, r.Stdout, `:\d\d {14}inside ~> notes\.txt$`)
+		assertContainsLine(t, r.Stdout, ` link-outside outside ~> /etc/shadow \[broken\]$`)
+		assertContains(t, r.Stdout, "\naudit: 1 finding in 1 file\n")
+	}
+}
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"github.com/canta2899/logo-ls/internal/cli"
+	"strings"
 	"testing"
 
 	"github.com/canta2899/logo-ls/pkg/fs/fakefs"
@@ -53,3 +54,126 @@ func TestIcons_HiddenFileHasGlyph(t *testing.T) {
 		t.Errorf("expected glyph for hidden entries, got:\n%s", r.Stdout)
 	}
 }
+
+func TestIcons_PathRulesUseAncestors(t *testing.T) {
+	vfs := fakefs.New(fakefs.Dir("root", dirMeta("9200"),
+		fakefs.Dir(".github", dirMeta("9201"),
+			fakefs.File("dependabot.yml", 10, mtime("2026-01-01 10:00:00"), fileMeta("9202")),
+			fakefs.Dir("workflows", dirMeta("9203"),
+				fakefs.File("ci.yml", 10, mtime("2026-01-01 10:00:00"), fileMeta("9204")),
+			),
+		),
+	))
+	r := runApp(t, vfs, "-1", "/root/.github/workflows")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: YAML files in .github/workflows get the GitHub Actions icon,
+	// in both short and long mode.
+	assertContains(t, r.Stdout, "\uf408 ci.yml")
+	r = runApp(t, vfs, "-l", "/root/.github/workflows")
+	assertContains(t, r.Stdout, "\uf408 ci.yml")
+
+	r = runApp(t, vfs, "-1", "/root/.github")
+	// Intent: the rule needs the workflows directory.
+	assertNotContains(t, r.Stdout, "\uf408")
+}
+
+func TestIcons_IconSets(t *testing.T) {
+	vfs := fakefs.New(smallTree())
+	r := runApp(t, vfs, "-1", "--icon-set=ascii", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	if inPUA(r.Stdout) {
+		t.Errorf("expected no nerd-font glyph with --icon-set=ascii, got:\n%s", r.Stdout)
+	}
+	// Intent: tags of different lengths are padded so the names line up.
+	got := lines(normalize(r.Stdout))
+	want := []string{"[readme] README.md", "[doc]    notes.txt", "[dir]    src/"}
+	for i := range want {
+		if i >= len(got) || strings.TrimRight(got[i], " ") != want[i] {
+			t.Fatalf("expected %q, got %q", want, got)
+		}
+	}
+
+	r = runApp(t, vfs, "-l", "--icon-set=emoji", "/root")
+	// Intent: emoji replace the glyphs in long mode too, and count as two
+	// cells when the columns are padded.
+	assertContains(t, r.Stdout, "\U0001f4d6 README.md")
+	assertContains(t, r.Stdout, "\U0001f4c1 src/")
+	if inPUA(r.Stdout) {
+		t.Errorf("expected no nerd-font glyph with --icon-set=emoji, got:\n%s", r.Stdout)
+	}
+
+	r = runApp(t, vfs, "-R", "--icon-set=ascii", "/root")
+	// Intent: the directory headers use the set too.
+	assertContains(t, r.Stdout, "[dir] /root/src:")
+}
+
+func TestIcons_Which(t *testing.T) {
+	vfs := fakefs.New(fakefs.Dir("root", dirMeta("9300"),
+		fakefs.Contents(fakefs.File(".logo-ls.yaml", 10, mtime("2026-01-01 10:00:00"), fileMeta("9301")),
+			"extensions:\n  md:\n    color: \"#ff0000\"\n"),
+		fakefs.File("notes.md", 10, mtime("2026-01-01 10:00:00"), fileMeta("9302")),
+		fakefs.Dir(".github", dirMeta("9303"),
+			fakefs.Dir("workflows", dirMeta("9304")),
+		),
+	))
+	r := runApp(t, vfs, "icons", "which", "/root/.github/workflows/ci.yml")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: the file need not exist; the built-in path rule is named.
+	assertContains(t, r.Stdout, "\uf408 #2088ff github-actions")
+	assertContains(t, r.Stdout, "built-in  paths: .github/workflows/*.yml")
+	assertNotContains(t, r.Stdout, "override")
+
+	r = runApp(t, vfs, "icons", "which", "/root/notes.md")
+	// Intent: the project file of the directory applies and is named.
+	assertContains(t, r.Stdout, "#ff0000")
+	assertContains(t, r.Stdout, "built-in  extensions: md")
+	assertContains(t, r.Stdout, "override  /root/.logo-ls.yaml (extensions: md)")
+
+	r = runApp(t, vfs, "icons", "which", "/root/.github")
+	// Intent: existing directories resolve as directories.
+	assertContains(t, r.Stdout, "built-in  directories: .github")
+}
+
+func TestIcons_ListAndSearch(t *testing.T) {
+	vfs := fakefs.New(smallTree())
+	r := runApp(t, vfs, "icons", "list")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	out := normalize(r.Stdout)
+	// Intent: every table is listed, in lookup order.
+	var last int
+	for _, section := range []string{" paths ", " files ", " sub_extensions ", " extensions ", " directories "} {
+		i := strings.Index(out, section)
+		if i < last {
+			t.Fatalf("expected %q after the previous table, got:\n%s", section, out)
+		}
+		last = i
+	}
+
+	r = runApp(t, vfs, "icons", "search", "PYTHON")
+	// Intent: search matches icon names as well as keys, ignoring case.
+	assertContains(t, r.Stdout, "extensions py ")
+	assertContains(t, r.Stdout, "files      requirements.txt")
+	assertNotContains(t, r.Stdout, " go ")
+}
+
+func TestIcons_BackgroundColors(t *testing.T) {
+	vfs := fakefs.New(fakefs.Dir("root", dirMeta("9400"),
+		fakefs.Contents(fakefs.File(".logo-ls.yaml", 10, mtime("2026-01-01 10:00:00"), fileMeta("9401")),
+			"extensions:\n  md:\n    color: {dark: white, light: ansi:blue}\n"),
+		fakefs.File("notes.md", 10, mtime("2026-01-01 10:00:00"), fileMeta("9402")),
+	))
+	r := runApp(t, vfs, "-1", "--background=dark", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: the color for the chosen background is used.
+	if !strings.Contains(r.Stdout, "\033[38;2;255;255;255m") {
+		t.Errorf("expected the dark color, got %q", r.Stdout)
+	}
+	r = runApp(t, vfs, "-1", "--background=light", "/root")
+	if !strings.Contains(r.Stdout, "\033[34m") || strings.Contains(r.Stdout, "\033[38;2;255;255;255m") {
+		t.Errorf("expected the light color, got %q", r.Stdout)
+	}
+
+	r = runApp(t, vfs, "icons", "which", "--background=light", "/root/notes.md")
+	// Intent: icons which reports the color for the background too.
+	assertContains(t, r.Stdout, "#0000ee")
+}
@@ -0,0 +1,95 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/canta2899/logo-ls/internal/cli"
+	"github.com/canta2899/logo-ls/pkg/fs/fakefs"
+)
+
+// mountInfo describes the mounts of mountTree: root on 8:1, media on 8:17
+// and run on 0:25, as /proc/self/mountinfo lists them.
+const mountInfo = `22 1 8:1 / /root rw,relatime shared:1 - ext4 /dev/sda1 rw
+41 22 8:17 / /root/media rw,nosuid shared:20 - vfat /dev/sdb1 rw
+43 22 0:25 / /root/run rw,nosuid,nodev shared:5 - tmpfs tmpfs rw,mode=755
+`
+
+func mountTree() *fakefs.Entry {
+	return fakefs.Mount(fakefs.Dir("root", dirMeta("7000"),
+		fakefs.Dir("docs", dirMeta("7001"),
+			fakefs.File("a.txt", 10, mtime("2026-01-01 10:00:00"), fileMeta("7002")),
+		),
+		fakefs.Mount(fakefs.Dir("media", dirMeta("7003"),
+			fakefs.Dir("photos", dirMeta("7004"),
+				fakefs.File("b.jpg", 10, mtime("2026-01-01 10:00:00"), fileMeta("7005")),
+			),
+		), 8<<8|17),
+		fakefs.Mount(fakefs.Dir("run", dirMeta("7006"),
+			fakefs.File("lock", 0, mtime("2026-01-01 10:00:00"), fileMeta("7007")),
+		), 25),
+	), 8<<8|1)
+}
+
+func TestMount_Icon(t *testing.T) {
+	r := runApp(t, fakefs.New(mountTree()), "-1", "--icon-set=ascii", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: directories on another device than their parent get the
+	// mount icon; the others keep the directory icon.
+	assertContainsLine(t, r.Stdout, `^\[mnt\] +media/$`)
+	assertContainsLine(t, r.Stdout, `^\[mnt\] +run/$`)
+	assertContainsLine(t, r.Stdout, `^\[dir\] +docs/$`)
+
+	// Intent: below a mount point, directories are on the same device
+	// again.
+	r = runApp(t, fakefs.New(mountTree()), "-1", "--icon-set=ascii", "/root/media")
+	assertContainsLine(t, r.Stdout, `^\[dir\] +photos/$`)
+}
+
+func TestMount_OneFileSystem(t *testing.T) {
+	vfs := fakefs.New(mountTree())
+	r := runApp(t, vfs, "-R", "/root")
+	assertContains(t, r.Stdout, "/root/media/photos:")
+	assertContains(t, r.Stdout, "lock")
+
+	r = runApp(t, vfs, "-Rx", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: -x lists mount points but does not descend into them.
+	assertContains(t, r.Stdout, "media/")
+	assertContains(t, r.Stdout, "/root/docs:")
+	assertNotContains(t, r.Stdout, "/root/media:")
+	assertNotContains(t, r.Stdout, "photos")
+	assertNotContains(t, r.Stdout, "lock")
+
+	// Intent: -x works without icons too, where mount points are not
+	// otherwise looked for.
+	r = runApp(t, vfs, "-Rxe", "/root")
+	assertNotContains(t, r.Stdout, "photos")
+	assertContains(t, r.Stdout, "a.txt")
+
+	// Intent: an operand on another file system is still listed.
+	r = runApp(t, vfs, "-Rx", "/root/media")
+	assertContains(t, r.Stdout, "b.jpg")
+}
+
+func TestMount_FSType(t *testing.T) {
+	vfs := fakefs.New(mountTree(), fakefs.WithMountInfo(mountInfo))
+	r := runApp(t, vfs, "-l", "--fs-type", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: --fs-type shows the type of the file system each entry is
+	// on, after the group.
+	assertContainsLine(t, r.Stdout, `staff +ext4 +\d+ .*docs/$`)
+	assertContainsLine(t, r.Stdout, `staff +vfat +\d+ .*media/$`)
+	assertContainsLine(t, r.Stdout, `staff +tmpfs +\d+ .*run/$`)
+
+	// Intent: outside the long listing the type comes before the name.
+	r = runApp(t, vfs, "-1", "--fs-type", "/root/media")
+	assertContainsLine(t, r.Stdout, `^vfat .*photos/$`)
+
+	// Intent: without a mount table the type is unknown.
+	r = runApp(t, fakefs.New(mountTree()), "-l", "--fs-type", "/root")
+	assertContainsLine(t, r.Stdout, `staff +\? +\d+ .*docs/$`)
+
+	// Intent: the column is only there on request.
+	r = runApp(t, vfs, "-l", "/root")
+	assertNotContains(t, r.Stdout, "ext4")
+}
@@ -2,6 +2,8 @@ package tests
 
 import (
 	"github.com/canta2899/logo-ls/internal/cli"
+	"github.com/canta2899/logo-ls/internal/icons"
+	iofs "io/fs"
 	"strings"
 	"testing"
 
@@ -74,6 +76,50 @@ func TestFlag_G_NoGroupInLong(t *testing.T) {
 	assertNotContains(t, r.Stdout, " staff  ")
 }
 
+// idTree holds a file with a named owner and one whose ids have no name,
+// as in containers and on NFS mounts.
+func idTree() *fakefs.Entry {
+	named := fileMeta("7001")
+	named.UID, named.GID = 501, 20
+	orphan := fileMeta("7002")
+	orphan.Owner, orphan.Group = "", ""
+	orphan.UID, orphan.GID = 100042, 100043
+	return fakefs.Dir("root", dirMeta("7000"),
+		fakefs.File("named.txt", 10, mtime("2026-01-01 10:00:00"), named),
+		fakefs.File("orphan.txt", 10, mtime("2026-01-01 10:00:00"), orphan),
+	)
+}
+
+func TestFlag_n_NumericIDs(t *testing.T) {
+	vfs := fakefs.New(idTree())
+	r := runApp(t, vfs, "-le", "/root")
+	// Intent: ids without a name fall back to the number instead of a
+	// blank column.
+	assertContainsLine(t, r.Stdout, `^-rw-r--r-- +1 +100042 +100043 +10 .*orphan\.txt$`)
+	assertContainsLine(t, r.Stdout, `^-rw-r--r-- +1 +alice +staff +10 .*named\.txt$`)
+
+	r = runApp(t, vfs, "-ne", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: -n is a long listing with numeric ids.
+	assertContainsLine(t, r.Stdout, `^-rw-r--r-- +1 +501 +20 +10 .*named\.txt$`)
+	assertNotContains(t, r.Stdout, "alice")
+
+	r = runApp(t, vfs, "-noe", "/root")
+	// Intent: -o and -g still pick the columns.
+	assertContainsLine(t, r.Stdout, `^-rw-r--r-- +1 +501 +10 .*named\.txt$`)
+}
+
+func TestFlag_author(t *testing.T) {
+	vfs := fakefs.New(idTree())
+	r := runApp(t, vfs, "-le", "--author", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: the author follows the group and is the owner.
+	assertContainsLine(t, r.Stdout, `^-rw-r--r-- +1 +alice +staff +alice +10 .*named\.txt$`)
+	// Intent: --author shows even when -g hides the owner.
+	r = runApp(t, vfs, "-ge", "--author", "/root")
+	assertContainsLine(t, r.Stdout, `^-rw-r--r-- +1 +staff +alice +10 .*named\.txt$`)
+}
+
 func TestFlag_h_HumanReadable(t *testing.T) {
 	vfs := fakefs.New(fakefs.Dir("root", dirMeta("a"),
 		fakefs.File("big.bin", 2*1024*1024, mtime("2026-01-01 10:00:00"), fileMeta("b")),
@@ -257,6 +303,126 @@ func TestFlag_e_NoIcons(t *testing.T) {
 	}
 }
 
+func TestFlag_I_IgnorePattern(t *testing.T) {
+	vfs := fakefs.New(smallTree())
+	r := runApp(t, vfs, "-1e", "-I", "*.md", "--ignore=src", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: every -I pattern hides the matching entries.
+	if got := lines(r.Stdout); !equalSlice(got, []string{"notes.txt"}) {
+		t.Errorf("expected only notes.txt, got %q", got)
+	}
+}
+
+func TestFlag_I_DoesNotHideOperands(t *testing.T) {
+	vfs := fakefs.New(smallTree())
+	r := runApp(t, vfs, "-1e", "-I*.md", "/root/README.md")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: like ls, -I only filters directory contents, not operands.
+	assertContains(t, r.Stdout, "README.md")
+}
+
+func TestFlag_w_OutputWidth(t *testing.T) {
+	vfs := fakefs.New(smallTree())
+	r := runApp(t, vfs, "-e", "-w12", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: a narrow width forces one entry per row.
+	if got := lines(r.Stdout); len(got) != 3 {
+		t.Errorf("expected 3 rows with -w12, got %q", got)
+	}
+
+	r = runApp(t, vfs, "-e", "--width", "0", "/root")
+	// Intent: 0 lifts the limit, keeping every entry on one row.
+	if got := lines(r.Stdout); len(got) != 1 {
+		t.Errorf("expected 1 row with --width 0, got %q", got)
+	}
+}
+
+func TestFlag_color(t *testing.T) {
+	vfs := fakefs.New(smallTree())
+	r := runApp(t, vfs, "-1", "/root")
+	// Intent: colors stay on by default, even when not writing to a terminal.
+	// The assert helpers strip escapes, so look at the raw output.
+	if !strings.Contains(r.Stdout, "\x1b[") {
+		t.Errorf("expected colored output by default, got %q", r.Stdout)
+	}
+
+	for _, when := range []string{"never", "auto"} {
+		r = runApp(t, vfs, "-1", "-R", "--color="+when, "/root")
+		assertExitCode(t, cli.CodeOk, r.ExitCode)
+		// Intent: never, and auto off a terminal, drop every escape,
+		// including the ones in directory headers.
+		if strings.Contains(r.Stdout, "\x1b") {
+			t.Errorf("--color=%s: expected no escapes, got %q", when, r.Stdout)
+		}
+		assertContains(t, r.Stdout, "README.md")
+	}
+}
+
+func TestFlag_octalPermissions(t *testing.T) {
+	suid := execMeta("7101")
+	suid.Mode |= iofs.ModeSetuid
+	tmp := dirMeta("7102")
+	tmp.Mode = iofs.ModeDir | iofs.ModeSticky | 0o777
+	vfs := fakefs.New(fakefs.Dir("root", dirMeta("7100"),
+		fakefs.File("plain.txt", 10, mtime("2026-01-01 10:00:00"), fileMeta("7103")),
+		fakefs.File("su", 10, mtime("2026-01-01 10:00:00"), suid),
+		fakefs.Dir("tmp", tmp),
+	))
+	r := runApp(t, vfs, "-le", "--octal-permissions", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: the octal digits follow the mode string, with setuid, setgid
+	// and sticky in the leading digit.
+	assertContainsLine(t, r.Stdout, `^-rw-r--r-- +0644 +1 .*plain\.txt$`)
+	assertContainsLine(t, r.Stdout, `^-rwsr-xr-x +4755 +1 .*su\*?$`)
+	assertContainsLine(t, r.Stdout, `^drwxrwxrwt +1777 +2 .*tmp/$`)
+
+	r = runApp(t, vfs, "-le", "/root")
+	assertNotContains(t, r.Stdout, "0644")
+}
+
+func TestFlag_permissionColors(t *testing.T) {
+	vfs := fakefs.New(smallTree())
+	r := runApp(t, vfs, "-le", "--permission-colors", "--background=dark", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: each letter carries its own color; the escapes are raw, so
+	// look at the unstripped output.
+	read := "\x1b[38;2;249;226;175mr\x1b[0m"
+	write := "\x1b[38;2;243;139;168mw\x1b[0m"
+	if !strings.Contains(r.Stdout, read+write) {
+		t.Errorf("expected colored rw letters, got %q", r.Stdout)
+	}
+	// Intent: the layout is unchanged once the escapes are stripped.
+	plain := runApp(t, vfs, "-le", "/root")
+	if normalize(r.Stdout) != normalize(plain.Stdout) {
+		t.Errorf("expected the same layout as without colors:\n%s\nvs\n%s", normalize(r.Stdout), normalize(plain.Stdout))
+	}
+
+	// Intent: the palette follows the background.
+	r = runApp(t, vfs, "-le", "--permission-colors", "--background=light", "/root")
+	if strings.Contains(r.Stdout, read) || !strings.Contains(r.Stdout, "\x1b[38;2;223;142;029mr") {
+		t.Errorf("expected the light palette, got %q", r.Stdout)
+	}
+	r = runApp(t, vfs, "-le", "--permission-colors", "--color=never", "/root")
+	if strings.Contains(r.Stdout, "\x1b") {
+		t.Errorf("expected no escapes with --color=never, got %q", r.Stdout)
+	}
+}
+
+func TestFlag_sniff(t *testing.T) {
+	vfs := fakefs.New(fakefs.Dir("root", dirMeta("9300"),
+		fakefs.Contents(fakefs.File("deploy", 0, mtime("2026-01-01 10:00:00"), execMeta("9301")), "#!/usr/bin/env python3\n"),
+	))
+	python := icons.IconSet["python"].GetGlyph()
+	r := runApp(t, vfs, "-1", "/root")
+	// Intent: without --sniff the contents are never read.
+	assertNotContains(t, r.Stdout, python)
+
+	r = runApp(t, vfs, "-1", "--sniff", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: the shebang picks the icon of an extensionless script.
+	assertContains(t, r.Stdout, python+" deploy")
+}
+
 func equalSlice(a, b []string) bool {
 	if len(a) != len(b) {
 		return false
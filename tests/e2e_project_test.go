@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/canta2899/logo-ls/internal/cli"
+	"github.com/canta2899/logo-ls/pkg/fs/fakefs"
+)
+
+// names returns the entry names of a -1 listing, dropping the icon column.
+func names(out string) []string {
+	var got []string
+	for _, l := range lines(out) {
+		fields := strings.Fields(l)
+		got = append(got, fields[len(fields)-1])
+	}
+	return got
+}
+
+func TestProject_LayersFilesUpToGitRoot(t *testing.T) {
+	vfs := fakefs.New(projectTree())
+	r := runApp(t, vfs, "-1", "/root/repo/pkg")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	assertNoLogs(t, r)
+	// Intent: the repo-root file applies to the package; the package's own
+	// file wins where both define an icon.
+	assertContains(t, r.Stdout, "\ue7a8 a.dsl")
+	assertContains(t, r.Stdout, "\ue60b b.spec")
+	// Intent: the file above the git root is never read, so notes.txt is
+	// listed and a.dsl doesn't get its glyph.
+	assertNotContains(t, r.Stdout, "\ue62b")
+	// Intent: the project sort (size) applies and hide patterns drop entries.
+	if got, want := names(r.Stdout), []string{"b.spec", "c.go", "a.dsl", "notes.txt"}; !equalSlice(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestProject_OptionsWin(t *testing.T) {
+	vfs := fakefs.New(projectTree())
+	r := runApp(t, vfs, "-1", "-t", "/root/repo/pkg")
+	// Intent: an explicit sort replaces the project's default sort.
+	if got, want := names(r.Stdout), []string{"c.go", "a.dsl", "b.spec", "notes.txt"}; !equalSlice(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	r = runApp(t, vfs, "-1", "-A", "/root/repo/pkg")
+	// Intent: like --hide, -A lists hidden patterns again.
+	assertContains(t, r.Stdout, "c.gen.go")
+
+	r = runApp(t, vfs, "-1", "--no-project", "/root/repo/pkg")
+	// Intent: --no-project restores built-in icons, sorting and hiding.
+	assertNotContains(t, r.Stdout, "\ue7a8")
+	if got, want := names(r.Stdout), []string{"a.dsl", "b.spec", "c.gen.go", "c.go", "notes.txt"}; !equalSlice(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestProject_AppliesToSubdirectoriesUnderR(t *testing.T) {
+	vfs := fakefs.New(projectTree())
+	r := runApp(t, vfs, "-1R", "/root/repo")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: the listed directory's project covers the whole recursive
+	// listing, and a subdirectory's own file layers over it there.
+	assertNotContains(t, r.Stdout, "c.gen.go")
+	assertContains(t, r.Stdout, "\ue7a8 a.dsl")
+	assertContains(t, r.Stdout, "\ue60b b.spec")
+	assertNotContains(t, r.Stdout, "\ue615")
+
+	vfs = fakefs.New(fakefs.Dir("root", dirMeta("9300"),
+		fakefs.Dir("repo", dirMeta("9301"),
+			fakefs.Dir(".git", dirMeta("9302")),
+			fakefs.Contents(fakefs.File(".logo-ls.yaml", 0, mtime("2026-01-01 10:00:00"), fileMeta("9303")),
+				"hide: [\"*.gen.go\"]\n"),
+			fakefs.File("a.gen.go", 10, mtime("2026-01-01 10:00:00"), fileMeta("9304")),
+			fakefs.Dir("vendored", dirMeta("9305"),
+				fakefs.Dir(".git", dirMeta("9306")),
+				fakefs.File("b.gen.go", 10, mtime("2026-01-01 10:00:00"), fileMeta("9307")),
+			),
+		),
+	))
+	r = runApp(t, vfs, "-1R", "/root/repo")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: a nested git repository is a project of its own, so the outer
+	// repo's file does not reach into it.
+	assertNotContains(t, r.Stdout, "a.gen.go")
+	assertContains(t, r.Stdout, "b.gen.go")
+}
+
+func TestProject_DebugIcons(t *testing.T) {
+	vfs := fakefs.New(projectTree())
+	r := runApp(t, vfs, "-1", "--debug-icons", "/root/repo/pkg")
+	// Intent: the debug log names the file that supplied each icon.
+	assertContains(t, r.Stderr, "/root/repo/pkg/a.dsl: icon from /root/repo/.logo-ls.yaml")
+	assertContains(t, r.Stderr, "/root/repo/pkg/b.spec: icon from /root/repo/pkg/.logo-ls.yaml")
+	assertContains(t, r.Stderr, "/root/repo/pkg/c.go: icon from built-in icons")
+}
+
+func TestProject_KeepsValidEntries(t *testing.T) {
+	t.Setenv("HOME", "/root")
+	vfs := fakefs.New(fakefs.Dir("root", dirMeta("9400"),
+		fakefs.Contents(fakefs.File(".logo-ls.yaml", 10, mtime("2026-01-01 10:00:00"), fileMeta("9401")),
+			"sort: size\nextensions:\n  go: {color: nope}\n  dsl: {glyph: \"U+E7A8\", colour: red}\n"),
+		fakefs.File("a.dsl", 10, mtime("2026-01-01 10:00:00"), fileMeta("9402")),
+	))
+	r := runApp(t, vfs, "-1", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: the invalid entry and the unknown key are reported with their
+	// position, and the valid entry still applies.
+	assertContains(t, r.Stderr, "/root/.logo-ls.yaml:3:15: extensions: \"go\": invalid color")
+	assertContains(t, r.Stderr, "/root/.logo-ls.yaml:4:26: extensions: \"dsl\": unknown key \"colour\"")
+	assertNotContains(t, r.Stderr, "sort")
+	assertContains(t, r.Stdout, " a.dsl")
+}
+
+func TestProject_OnlyInsideGitRootOrHome(t *testing.T) {
+	vfs := fakefs.New(fakefs.Dir("root", dirMeta("9500"),
+		fakefs.Contents(fakefs.File(".logo-ls.yaml", 10, mtime("2026-01-01 10:00:00"), fileMeta("9501")),
+			"hide: [\"*.txt\"]\n"),
+		fakefs.File("notes.txt", 10, mtime("2026-01-01 10:00:00"), fileMeta("9502")),
+	))
+	t.Setenv("HOME", "/home/someone")
+	r := runApp(t, vfs, "-1", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	assertNoLogs(t, r)
+	// Intent: /root is under neither a git root nor $HOME, so its project
+	// file is not read and notes.txt stays listed.
+	assertContains(t, r.Stdout, "notes.txt")
+
+	t.Setenv("HOME", "/root")
+	r = runApp(t, vfs, "-1", "/root")
+	// Intent: the same file applies once the directory is $HOME.
+	assertNotContains(t, r.Stdout, "notes.txt")
+}
+
+func TestProject_SkipsForeignOwner(t *testing.T) {
+	t.Setenv("HOME", "/root")
+	foreign := fileMeta("9511")
+	foreign.UID = 4242
+	vfs := fakefs.New(fakefs.Dir("root", dirMeta("9510"),
+		fakefs.Contents(fakefs.File(".logo-ls.yaml", 10, mtime("2026-01-01 10:00:00"), foreign),
+			"hide: [\"*.txt\"]\n"),
+		fakefs.File("notes.txt", 10, mtime("2026-01-01 10:00:00"), fileMeta("9512")),
+	))
+	r := runApp(t, vfs, "-1", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: a project file someone else owns never hides entries.
+	assertContains(t, r.Stdout, "notes.txt")
+	assertContains(t, r.Stderr, "ignoring project file /root/.logo-ls.yaml: not owned by you or root")
+}
@@ -0,0 +1,57 @@
+package tests
+
+import (
+	iofs "io/fs"
+	"testing"
+
+	"github.com/canta2899/logo-ls/internal/cli"
+	"github.com/canta2899/logo-ls/pkg/fs/fakefs"
+)
+
+func devTree() *fakefs.Entry {
+	meta := func(inode string) fakefs.Meta {
+		m := fileMeta(inode)
+		m.Owner, m.Group, m.Mode = "root", "disk", 0o660
+		return m
+	}
+	return fakefs.Dir("dev", dirMeta("9500"),
+		fakefs.BlockDevice("sda", 8, 0, meta("9501")),
+		fakefs.BlockDevice("sda1", 8, 1, meta("9502")),
+		fakefs.CharDevice("null", 1, 3, meta("9503")),
+		fakefs.Pipe("initctl", meta("9504")),
+		fakefs.Socket("log", meta("9505")),
+		fakefs.File("mount", 10, mtime("2026-01-01 10:00:00"), fakefs.Meta{Mode: 0o755 | iofs.ModeSetuid, Owner: "root", Group: "root"}),
+	)
+}
+
+func TestSpecial_LongListing(t *testing.T) {
+	vfs := fakefs.New(devTree())
+	r := runApp(t, vfs, "-l", "/dev")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	assertNoLogs(t, r)
+	// Intent: devices show their type letter and major, minor instead of a size.
+	assertContains(t, r.Stdout, "brw-rw----")
+	assertContains(t, r.Stdout, "8,   1")
+	assertContains(t, r.Stdout, "crw-rw----")
+	assertContains(t, r.Stdout, "1,   3")
+	assertContains(t, r.Stdout, "prw-rw----")
+	assertContains(t, r.Stdout, "srw-rw----")
+	// Intent: setuid shows as s in the owner's execute slot.
+	assertContains(t, r.Stdout, "-rwsr-xr-x")
+}
+
+func TestSpecial_Icons(t *testing.T) {
+	vfs := fakefs.New(devTree())
+	r := runApp(t, vfs, "-1", "--icon-set=ascii", "/dev")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: special files get an icon for their type, whatever their name;
+	// setuid programs are set apart from plain executables.
+	assertContains(t, r.Stdout, "[pipe] initctl|")
+	assertContains(t, r.Stdout, "[sock] log=")
+	assertContains(t, r.Stdout, "[suid] mount*")
+	assertContains(t, r.Stdout, "[chr]  null")
+	assertContains(t, r.Stdout, "[blk]  sda")
+
+	r = runApp(t, vfs, "icons", "which", "/dev/sda")
+	assertContains(t, r.Stdout, "built-in  default: blockdevice")
+}
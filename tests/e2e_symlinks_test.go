@@ -2,6 +2,7 @@ package tests
 
 import (
 	"github.com/canta2899/logo-ls/internal/cli"
+	"strings"
 	"testing"
 
 	"github.com/canta2899/logo-ls/pkg/fs/fakefs"
@@ -16,10 +17,11 @@ func TestSymlink_OneLineIndicator(t *testing.T) {
 	r := runApp(t, vfs, "-1e", "/root")
 	assertGolden(t, "symlink_oneline", r.Stdout)
 	assertExitCode(t, cli.CodeOk, r.ExitCode)
-	// All three links rendered with '@'.
-	assertContains(t, r.Stdout, "link-file@")
-	assertContains(t, r.Stdout, "link-dir@")
-	assertContains(t, r.Stdout, "link-broken@")
+	// All three links rendered with '@', the dangling one with '@!' even
+	// though -e leaves no icon to tell it apart.
+	assertContainsLine(t, r.Stdout, `^link-file@$`)
+	assertContainsLine(t, r.Stdout, `^link-dir@$`)
+	assertContainsLine(t, r.Stdout, `^link-broken@!$`)
 }
 
 func TestSymlink_LongModeShowsLPrefix(t *testing.T) {
@@ -27,8 +29,150 @@ func TestSymlink_LongModeShowsLPrefix(t *testing.T) {
 	r := runApp(t, vfs, "-le", "/root")
 	assertGolden(t, "symlink_long", r.Stdout)
 	assertExitCode(t, cli.CodeOk, r.ExitCode)
-	// Long mode: mode column starts with 'l', resolvable links show "~> target".
-	assertContainsLine(t, r.Stdout, `^l.*link-file ~> /root/target\.txt`)
-	assertContainsLine(t, r.Stdout, `^l.*link-dir ~> /root/subdir`)
-	assertContainsLine(t, r.Stdout, `^l.*link-broken`)
+	// Long mode: mode column starts with 'l', links show "~> target" with
+	// the target as stored, like ls; broken ones are marked.
+	assertContainsLine(t, r.Stdout, `^l.*link-file ~> target\.txt$`)
+	assertContainsLine(t, r.Stdout, `^l.*link-dir ~> subdir$`)
+	assertContainsLine(t, r.Stdout, `^l.*link-broken ~> nowhere \[broken\]$`)
+}
+
+func TestSymlink_UnderSymlinkedDir(t *testing.T) {
+	vfs := fakefs.New(fakefs.Dir("root", dirMeta("4200"),
+		fakefs.Dir("real", dirMeta("4201"),
+			fakefs.Dir("sub", dirMeta("4202"),
+				fakefs.Symlink("up", "../target.txt", symlinkMeta("4203")),
+				fakefs.Symlink("hop", "up", symlinkMeta("4204")),
+			),
+			fakefs.File("target.txt", 100, mtime("2026-01-01 10:00:00"), fileMeta("4205")),
+		),
+		fakefs.Symlink("alias", "real/sub", symlinkMeta("4206")),
+	))
+	r := runApp(t, vfs, "-lH", "--link-chain", "/root/alias")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	assertNoLogs(t, r)
+	// Intent: ".." in a link under a symlinked directory is taken from
+	// where the directory really is, so neither link is broken.
+	assertContainsLine(t, r.Stdout, `up ~> \.\./target\.txt$`)
+	assertContainsLine(t, r.Stdout, `hop ~> up ~> \.\./target\.txt$`)
+	assertNotContains(t, r.Stdout, "[broken]")
+}
+
+func TestSymlink_BrokenLoopAndChain(t *testing.T) {
+	// Keep absolute targets under /root from being folded to ~.
+	t.Setenv("HOME", "/home/someone")
+	vfs := fakefs.New(fakefs.Dir("root", dirMeta("4100"),
+		fakefs.File("target.txt", 100, mtime("2026-01-01 10:00:00"), fileMeta("4101")),
+		fakefs.Symlink("hop1", "hop2", symlinkMeta("4102")),
+		fakefs.Symlink("hop2", "/root/target.txt", symlinkMeta("4103")),
+		fakefs.Symlink("loop-a", "loop-b", symlinkMeta("4104")),
+		fakefs.Symlink("loop-b", "loop-a", symlinkMeta("4105")),
+		fakefs.Symlink("dangling", "hop-missing", symlinkMeta("4106")),
+		fakefs.Symlink("via-dangling", "dangling", symlinkMeta("4107")),
+	))
+	r := runApp(t, vfs, "-l", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	assertNoLogs(t, r)
+	// Intent: only the first hop is shown by default; the link still resolves
+	// through the chain.
+	assertContainsLine(t, r.Stdout, `hop1 ~> hop2$`)
+	// Intent: loops are told apart from missing targets.
+	assertContainsLine(t, r.Stdout, `loop-a ~> loop-b \[loop\]$`)
+	assertContainsLine(t, r.Stdout, `dangling ~> hop-missing \[broken\]$`)
+	// Intent: a link is broken when a later hop is.
+	assertContainsLine(t, r.Stdout, `via-dangling ~> dangling \[broken\]$`)
+	// Intent: broken links get their own icon.
+	assertContains(t, r.Stdout, "\U000f0338 dangling")
+	assertNotContains(t, r.Stdout, "\U000f0338 hop1")
+
+	r = runApp(t, vfs, "-l", "--link-chain", "/root")
+	// Intent: --link-chain shows every hop as stored.
+	assertContainsLine(t, r.Stdout, `hop1 ~> hop2 ~> /root/target\.txt$`)
+	assertContainsLine(t, r.Stdout, `via-dangling ~> dangling ~> hop-missing \[broken\]$`)
+	assertContainsLine(t, r.Stdout, `loop-a ~> loop-b ~> loop-a \[loop\]$`)
+
+	r = runApp(t, vfs, "-1", "/root")
+	// Intent: short mode marks broken links @! and draws the broken icon.
+	assertContains(t, r.Stdout, "\U000f0338 loop-a@!")
+	assertContains(t, r.Stdout, "\U000f0338 via-dangling@!")
+	assertContainsLine(t, r.Stdout, ` hop1@$`)
+
+	r = runApp(t, vfs, "-1e", "/root")
+	// Intent: without icons the links are still resolved, so broken and
+	// looping ones keep their own indicator.
+	assertContainsLine(t, r.Stdout, `^dangling@!$`)
+	assertContainsLine(t, r.Stdout, `^loop-b@!$`)
+	assertContainsLine(t, r.Stdout, `^hop1@$`)
+}
+
+func TestSymlink_Dereference(t *testing.T) {
+	vfs := fakefs.New(treeWithSymlinks())
+
+	r := runApp(t, vfs, "-l", "-L", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: -L shows the target's metadata instead of the link's.
+	assertContainsLine(t, r.Stdout, `^-.* 100 .*link-file$`)
+	assertContainsLine(t, r.Stdout, `^d.*link-dir/?$`)
+	assertNotContains(t, r.Stdout, "link-file ~>")
+	// Intent: a broken link has no target to show and stays a link.
+	assertContainsLine(t, r.Stdout, `^l.*link-broken ~> nowhere \[broken\]$`)
+
+	// Intent: command-line links to directories are followed without -l
+	// and kept as links with it, like GNU ls.
+	r = runApp(t, vfs, "-1", "/root/link-dir")
+	assertContains(t, r.Stdout, "inner.txt")
+	r = runApp(t, vfs, "-l", "/root/link-dir")
+	assertContainsLine(t, r.Stdout, `^l.*link-dir ~> subdir$`)
+	r = runApp(t, vfs, "-l", "--dereference-command-line-symlink-to-dir", "/root/link-dir")
+	assertContains(t, r.Stdout, "inner.txt")
+
+	// Intent: -H follows command-line links only.
+	r = runApp(t, vfs, "-l", "-H", "/root/link-file", "/root/link-dir")
+	assertContainsLine(t, r.Stdout, `^-.* 100 .*link-file$`)
+	assertContains(t, r.Stdout, "inner.txt")
+	r = runApp(t, vfs, "-l", "-H", "/root")
+	assertContainsLine(t, r.Stdout, `^l.*link-file ~> target\.txt$`)
+}
+
+func TestSymlink_DereferenceRecursiveLoop(t *testing.T) {
+	vfs := fakefs.New(fakefs.Dir("root", dirMeta("4200"),
+		fakefs.Dir("sub", dirMeta("4201"),
+			fakefs.File("inner.txt", 50, mtime("2026-01-02 10:00:00"), fileMeta("4202")),
+			fakefs.Symlink("up", "/root", symlinkMeta("4203")),
+		),
+	))
+	r := runApp(t, vfs, "-R", "-L", "/root")
+	// Intent: a link back to an ancestor is listed once and not descended
+	// into, and the run reports it like GNU ls.
+	assertExitCode(t, cli.CodeSerious, r.ExitCode)
+	assertContains(t, r.Stderr, "sub/up: not listing already-listed directory")
+	if n := strings.Count(r.Stdout, "inner.txt"); n != 1 {
+		t.Errorf("expected inner.txt listed once, got %d times:\n%s", n, r.Stdout)
+	}
+
+	// Intent: without -L the link is not followed at all.
+	r = runApp(t, vfs, "-R", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	assertNoLogs(t, r)
+}
+
+func TestSymlink_TrailingSlashFollowsLink(t *testing.T) {
+	vfs := fakefs.New(treeWithSymlinks())
+
+	// Intent: "link-dir/" names the directory behind the link, so it is
+	// listed whatever the dereference mode, like GNU ls.
+	for _, mode := range [][]string{
+		{"-l"},
+		{"-l", "--dereference-command-line-symlink-to-dir"},
+		{"-l", "-H"},
+		{"-l", "-L"},
+	} {
+		r := runApp(t, vfs, append(mode, "/root/link-dir/")...)
+		assertExitCode(t, cli.CodeOk, r.ExitCode)
+		assertContains(t, r.Stdout, "inner.txt")
+		assertNotContains(t, r.Stdout, "~> subdir")
+	}
+
+	// Intent: without the slash -l still lists the link itself.
+	r := runApp(t, vfs, "-l", "/root/link-dir")
+	assertContainsLine(t, r.Stdout, `^l.*link-dir ~> subdir$`)
 }
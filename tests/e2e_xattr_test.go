@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/canta2899/logo-ls/internal/cli"
+	"github.com/canta2899/logo-ls/pkg/fs/fakefs"
+)
+
+func xattrTree() *fakefs.Entry {
+	tagged := fileMeta("6001")
+	tagged.XAttrs = map[string]string{
+		"user.xdg.origin.url": "https://example.org/a.tar",
+		"user.checksum":       "\x01\x02",
+	}
+	labelled := fileMeta("6002")
+	labelled.XAttrs = map[string]string{"security.selinux": "unconfined_u:object_r:user_home_t:s0\x00"}
+	shared := fileMeta("6004")
+	shared.XAttrs = map[string]string{
+		"system.posix_acl_access": "\x02\x00\x00\x00",
+		"user.note":               "x",
+	}
+	return fakefs.Dir("root", dirMeta("6000"),
+		fakefs.File("a.tar", 10, mtime("2026-01-01 10:00:00"), tagged),
+		fakefs.File("labelled.txt", 10, mtime("2026-01-01 10:00:00"), labelled),
+		fakefs.File("plain.txt", 10, mtime("2026-01-01 10:00:00"), fileMeta("6003")),
+		fakefs.File("shared.txt", 10, mtime("2026-01-01 10:00:00"), shared),
+	)
+}
+
+func TestXAttr_Marker(t *testing.T) {
+	r := runApp(t, fakefs.New(xattrTree()), "-l", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: the long listing marks files with extended attributes.
+	assertContainsLine(t, r.Stdout, `^-rw-r--r--@ .*a\.tar$`)
+	assertContainsLine(t, r.Stdout, `^-rw-r--r-- .*plain\.txt$`)
+	// Intent: SELinux labels, present on every file where they are used,
+	// do not earn the marker.
+	assertContainsLine(t, r.Stdout, `^-rw-r--r-- .*labelled\.txt$`)
+	// Intent: attributes are only listed on request.
+	assertNotContains(t, r.Stdout, "user.checksum")
+}
+
+func TestXAttr_List(t *testing.T) {
+	vfs := fakefs.New(xattrTree())
+	r := runApp(t, vfs, "-l@", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: each attribute is listed by name and size under its file.
+	assertContains(t, r.Stdout, "a.tar\n\tuser.checksum\t     2\n\tuser.xdg.origin.url\t    25\n")
+	assertContains(t, r.Stdout, "labelled.txt\n\tsecurity.selinux\t    37\n")
+	assertNotContains(t, r.Stdout, "https://")
+
+	r = runApp(t, vfs, "-l", "--xattr-values", "/root")
+	// Intent: --xattr-values prints values quoted so binary data stays on
+	// one line.
+	assertContains(t, r.Stdout, "\tuser.checksum\t     2 \"\\x01\\x02\"\n")
+	assertContains(t, r.Stdout, "\tuser.xdg.origin.url\t    25 \"https://example.org/a.tar\"\n")
+
+	// Intent: outside the long listing -@ changes nothing.
+	r = runApp(t, vfs, "-1@", "/root")
+	assertNotContains(t, r.Stdout, "user.checksum")
+}
+
+func TestXAttr_ACLAndContext(t *testing.T) {
+	vfs := fakefs.New(xattrTree())
+	r := runApp(t, vfs, "-l", "/root")
+	// Intent: an ACL is marked with +, like GNU ls, and takes the place of
+	// the @ marker.
+	assertContainsLine(t, r.Stdout, `^-rw-r--r--\+ .*shared\.txt$`)
+
+	r = runApp(t, vfs, "-lZ", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: -Z shows the context after the group, and ? for files
+	// without one.
+	assertContainsLine(t, r.Stdout, `staff +unconfined_u:object_r:user_home_t:s0 +10 .*labelled\.txt$`)
+	assertContainsLine(t, r.Stdout, `staff +\? +10 .*plain\.txt$`)
+
+	// Intent: outside the long listing the context comes before the name.
+	r = runApp(t, vfs, "-1Z", "/root")
+	assertContainsLine(t, r.Stdout, `^unconfined_u:object_r:user_home_t:s0 .*labelled\.txt$`)
+	assertContainsLine(t, r.Stdout, `\? .*plain\.txt$`)
+}
+
+func TestAttributes_Column(t *testing.T) {
+	locked := fileMeta("6101")
+	locked.Flags = 0x10 | 0x80000 // immutable, extents
+	ping := execMeta("6102")
+	ping.XAttrs = map[string]string{"security.capability": "\x01\x00\x00\x02\x00\x20\x00\x00"}
+	vfs := fakefs.New(fakefs.Dir("root", dirMeta("6100"),
+		fakefs.File("locked.conf", 10, mtime("2026-01-01 10:00:00"), locked),
+		fakefs.File("ping", 10, mtime("2026-01-01 10:00:00"), ping),
+		fakefs.Symlink("link", "ping", symlinkMeta("6103")),
+	))
+
+	r := runApp(t, vfs, "-l", "/root")
+	// Intent: the column is opt-in.
+	assertNotContains(t, r.Stdout, "----i")
+	assertNotContains(t, r.Stdout, " cap ")
+
+	r = runApp(t, vfs, "-l", "--attributes", "/root")
+	assertExitCode(t, cli.CodeOk, r.ExitCode)
+	// Intent: flags are shown after the mode in lsattr's order and layout.
+	assertContainsLine(t, r.Stdout, `^-rw-r--r-- +----i---------e------- +1 .*locked\.conf$`)
+	// Intent: file capabilities are marked, and keep the @ marker.
+	assertContainsLine(t, r.Stdout, `^-rwxr-xr-x@ +---------------------- cap +1 .*ping\*?$`)
+	// Intent: symlinks have no flags to read.
+	assertContainsLine(t, r.Stdout, `^lrwxrwxrwx +\? +1 .*link`)
+}
@@ -191,3 +191,26 @@ func execTree() *fakefs.Entry {
 		fakefs.File("run.sh", 20, mtime("2026-01-02 10:00:00"), execMeta("8002")),
 	)
 }
+
+// projectTree: a git repository with project files at its root and in a
+// package, plus one outside the repository that must never apply.
+func projectTree() *fakefs.Entry {
+	return fakefs.Dir("root", dirMeta("9100"),
+		fakefs.Contents(fakefs.File(".logo-ls.yaml", 0, mtime("2026-01-01 10:00:00"), fileMeta("9101")),
+			"hide: [\"*.txt\"]\nextensions:\n  dsl:\n    glyph: \"U+E62B\"\n"),
+		fakefs.Dir("repo", dirMeta("9102"),
+			fakefs.Dir(".git", dirMeta("9103")),
+			fakefs.Contents(fakefs.File(".logo-ls.yaml", 0, mtime("2026-01-01 10:00:00"), fileMeta("9104")),
+				"sort: size\nhide: [\"*.gen.go\"]\nextensions:\n  dsl:\n    glyph: \"U+E7A8\"\n  spec:\n    glyph: \"U+E615\"\n"),
+			fakefs.Dir("pkg", dirMeta("9105"),
+				fakefs.Contents(fakefs.File(".logo-ls.yaml", 0, mtime("2026-01-01 10:00:00"), fileMeta("9106")),
+					"extensions:\n  spec:\n    glyph: \"U+E60B\"\n"),
+				fakefs.File("a.dsl", 10, mtime("2026-01-03 10:00:00"), fileMeta("9107")),
+				fakefs.File("b.spec", 300, mtime("2026-01-02 10:00:00"), fileMeta("9108")),
+				fakefs.File("c.go", 200, mtime("2026-01-04 10:00:00"), fileMeta("9109")),
+				fakefs.File("c.gen.go", 100, mtime("2026-01-01 10:00:00"), fileMeta("9110")),
+				fakefs.File("notes.txt", 5, mtime("2026-01-01 10:00:00"), fileMeta("9111")),
+			),
+		),
+	)
+}
@@ -58,6 +58,10 @@ func runApp(t *testing.T, vfs fs.FS, args ...string) runResult {
 	// Force --no-override so the harness never picks up the developer's
 	// personal icon overrides from $HOME. Keeps goldens reproducible.
 	argv := append([]string{"logo-ls", "--no-override"}, args...)
+	if len(args) > 0 && args[0] == "icons" {
+		// an icons command only counts as the first argument
+		argv = append([]string{"logo-ls", "icons", "--no-override"}, args[1:]...)
+	}
 	cfg, _, err := cli.BuildConfig(argv)
 	if err != nil {
 		t.Fatalf("BuildConfig(%v): %v", args, err)